@@ -0,0 +1,111 @@
+package config
+
+import (
+	"slices"
+	"sort"
+)
+
+// Change describes a single key whose value changed on reload
+type Change struct {
+	Key string // Environment variable name, e.g. DB_POOL_SIZE
+	Old string // Value before the reload, "" if the key was added
+	New string // Value after the reload, "" if the key was removed
+}
+
+// ChangeSet lists the keys that changed on reload, each sorted by key
+type ChangeSet struct {
+	Added    []Change
+	Removed  []Change
+	Modified []Change
+}
+
+// Empty reports whether no key changed
+func (cs ChangeSet) Empty() bool {
+	return len(cs.Added) == 0 && len(cs.Removed) == 0 && len(cs.Modified) == 0
+}
+
+// Get returns the change of key, if it changed
+func (cs ChangeSet) Get(key string) (Change, bool) {
+	key = toEnvKey(key)
+	for _, changes := range [][]Change{cs.Added, cs.Removed, cs.Modified} {
+		for _, change := range changes {
+			if change.Key == key {
+				return change, true
+			}
+		}
+	}
+	return Change{}, false
+}
+
+// OnChange registers fn to be called after each Reload, SetFile or Watch
+// reload that changes at least one value. Functions are called in the order
+// they were registered, on the goroutine that reloaded, after the new values
+// are in place.
+func (c *Config) OnChange(fn func(ChangeSet)) {
+	if c.root != nil {
+		prefix := c.prefix
+		c.root.OnChange(func(changes ChangeSet) {
+			if scoped := changes.scoped(prefix); !scoped.Empty() {
+				fn(scoped)
+			}
+		})
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.subscribers = append(c.subscribers, fn)
+}
+
+// OnKeyChange registers fn to be called when a reload adds, removes or modifies key
+func (c *Config) OnKeyChange(key string, fn func(Change)) {
+	c.OnChange(func(changes ChangeSet) {
+		if change, ok := changes.Get(key); ok {
+			fn(change)
+		}
+	})
+}
+
+// update applies fn under the lock and notifies subscribers of the values it changed
+func (c *Config) update(fn func() error) error {
+	c.mu.Lock()
+	previous := c.state.Load()
+	err := fn()
+	changes := diffValues(previous.values, c.state.Load().values)
+	subscribers := slices.Clone(c.subscribers)
+	c.mu.Unlock()
+
+	if err != nil {
+		return err
+	}
+	if !changes.Empty() {
+		for _, fn := range subscribers {
+			fn(changes)
+		}
+	}
+	return nil
+}
+
+// diffValues compares two sets of loaded values
+func diffValues(previous, current map[string]string) ChangeSet {
+	var changes ChangeSet
+	for key, value := range current {
+		old, ok := previous[key]
+		switch {
+		case !ok:
+			changes.Added = append(changes.Added, Change{Key: key, New: value})
+		case old != value:
+			changes.Modified = append(changes.Modified, Change{Key: key, Old: old, New: value})
+		}
+	}
+	for key, old := range previous {
+		if _, ok := current[key]; !ok {
+			changes.Removed = append(changes.Removed, Change{Key: key, Old: old})
+		}
+	}
+
+	for _, list := range [][]Change{changes.Added, changes.Removed, changes.Modified} {
+		sort.Slice(list, func(i, j int) bool { return list[i].Key < list[j].Key })
+	}
+	return changes
+}
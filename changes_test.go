@@ -0,0 +1,71 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestOnChange(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "changes.yaml")
+	if err := os.WriteFile(path, []byte("db:\n  pool_size: 10\n  host: db.local\ncache: redis\n"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	config := NewWithOptions(path, WithIsolated())
+
+	var got []ChangeSet
+	config.OnChange(func(changes ChangeSet) {
+		got = append(got, changes)
+	})
+	var poolChanges []Change
+	config.OnKeyChange("db.pool_size", func(change Change) {
+		poolChanges = append(poolChanges, change)
+	})
+
+	// Reloading unchanged values notifies nobody
+	if err := config.Reload(); err != nil {
+		t.Fatalf("Failed to reload: %v", err)
+	}
+	if len(got) != 0 {
+		t.Fatalf("Expected no notification for an unchanged reload, got %+v", got)
+	}
+
+	if err := os.WriteFile(path, []byte("db:\n  pool_size: 20\n  host: db.local\nqueue: nats\n"), 0644); err != nil {
+		t.Fatalf("Failed to update test file: %v", err)
+	}
+	if err := config.Reload(); err != nil {
+		t.Fatalf("Failed to reload: %v", err)
+	}
+
+	expected := ChangeSet{
+		Added:    []Change{{Key: "QUEUE", New: "nats"}},
+		Removed:  []Change{{Key: "CACHE", Old: "redis"}},
+		Modified: []Change{{Key: "DB_POOL_SIZE", Old: "10", New: "20"}},
+	}
+	if len(got) != 1 || !reflect.DeepEqual(got[0], expected) {
+		t.Fatalf("Expected %+v, got %+v", expected, got)
+	}
+	if len(poolChanges) != 1 || poolChanges[0].New != "20" {
+		t.Errorf("Expected one DB_POOL_SIZE change to 20, got %+v", poolChanges)
+	}
+
+	other := filepath.Join(dir, "changes.env")
+	if err := os.WriteFile(other, []byte("DB_POOL_SIZE=5\n"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	if err := config.SetFile(other); err != nil {
+		t.Fatalf("Failed to set file: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("Expected SetFile to notify, got %d notifications", len(got))
+	}
+	if change, ok := got[1].Get("DB_HOST"); !ok || change.Old != "db.local" || change.New != "" {
+		t.Errorf("Expected DB_HOST to be removed, got %+v", change)
+	}
+	if len(poolChanges) != 2 || poolChanges[1].Old != "20" || poolChanges[1].New != "5" {
+		t.Errorf("Expected DB_POOL_SIZE to change from 20 to 5, got %+v", poolChanges)
+	}
+}
@@ -0,0 +1,177 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// Run with -race to check readers and reloaders for data races
+func TestConcurrentReadersAndReloads(t *testing.T) {
+	for name, opts := range map[string][]Option{
+		"isolated": {WithIsolated()},
+		"env":      nil,
+	} {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			first := filepath.Join(dir, "first.env")
+			second := filepath.Join(dir, "second.yaml")
+			if err := os.WriteFile(first, []byte("RACE_HOST=first\nRACE_PORT=1\nRACE_DEBUG=true\n"), 0644); err != nil {
+				t.Fatalf("Failed to create test file: %v", err)
+			}
+			if err := os.WriteFile(second, []byte("race:\n  host: second\n  port: 2\n  debug: false\n"), 0644); err != nil {
+				t.Fatalf("Failed to create test file: %v", err)
+			}
+
+			config := NewWithOptions(first, opts...)
+			defer config.SetFile("")
+
+			stop := make(chan struct{})
+			var readers sync.WaitGroup
+			for i := 0; i < 4; i++ {
+				readers.Add(1)
+				go func() {
+					defer readers.Done()
+					type race struct {
+						Host string `config:"HOST"`
+						Port int    `config:"PORT"`
+					}
+					for {
+						select {
+						case <-stop:
+							return
+						default:
+						}
+						config.Str("RACE_HOST")
+						config.Int("RACE_PORT")
+						config.Bool("RACE_DEBUG")
+						config.Origin("RACE_HOST")
+						var target struct {
+							Race race `config:"RACE"`
+						}
+						config.Unmarshal(&target)
+						config.Snapshot().Str("RACE_HOST")
+					}
+				}()
+			}
+
+			config.OnChange(func(ChangeSet) {
+				config.Str("RACE_HOST")
+			})
+			for i := 0; i < 100; i++ {
+				file := first
+				if i%2 == 1 {
+					file = second
+				}
+				if err := config.SetFile(file); err != nil {
+					t.Errorf("Failed to set file: %v", err)
+				}
+				if err := config.Reload(); err != nil {
+					t.Errorf("Failed to reload config: %v", err)
+				}
+			}
+			close(stop)
+			readers.Wait()
+		})
+	}
+}
+
+func TestSnapshotConsistency(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshot.env")
+	write := func(n int) {
+		content := fmt.Sprintf("SNAP_A=%d\nSNAP_B=%d\n", n, n)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write test file: %v", err)
+		}
+	}
+	write(0)
+
+	config := NewWithOptions(path, WithIsolated())
+	snapshot := config.Snapshot()
+
+	stop := make(chan struct{})
+	mismatch := make(chan string, 1)
+	var readers sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		readers.Add(1)
+		go func() {
+			defer readers.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				view := config.Snapshot()
+				if a, b := view.Int("SNAP_A"), view.Int("SNAP_B"); a != b {
+					select {
+					case mismatch <- fmt.Sprintf("SNAP_A=%d SNAP_B=%d", a, b):
+					default:
+					}
+					return
+				}
+			}
+		}()
+	}
+
+	for n := 1; n <= 100; n++ {
+		write(n)
+		if err := config.Reload(); err != nil {
+			t.Fatalf("Failed to reload config: %v", err)
+		}
+	}
+	close(stop)
+	readers.Wait()
+
+	select {
+	case values := <-mismatch:
+		t.Errorf("Expected snapshots to be consistent, got %s", values)
+	default:
+	}
+
+	if value := snapshot.Int("SNAP_A"); value != 0 {
+		t.Errorf("Expected the first snapshot to keep SNAP_A=0, got %d", value)
+	}
+	if value := config.Int("SNAP_A"); value != 100 {
+		t.Errorf("Expected SNAP_A=100, got %d", value)
+	}
+	if err := snapshot.Reload(); err == nil {
+		t.Error("Expected Reload to fail on a snapshot")
+	}
+	if err := snapshot.SetFile(path); err == nil {
+		t.Error("Expected SetFile to fail on a snapshot")
+	}
+}
+
+// Run with -race to check reading the files of a cascade while it reloads
+func TestDotenvFilesDuringReload(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ".env"), []byte("RACE_CASCADE=1\n"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	cascade := &Dotenv{Dir: dir, Env: "test"}
+	config := NewWithOptions("", WithIsolated(), WithSources(cascade))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			if err := config.Reload(); err != nil {
+				t.Errorf("Failed to reload config: %v", err)
+			}
+		}
+	}()
+	for {
+		select {
+		case <-done:
+			if files := cascade.Files(); len(files) != 1 {
+				t.Errorf("Expected one file, got %v", files)
+			}
+			return
+		default:
+			cascade.Files()
+		}
+	}
+}
@@ -272,6 +272,9 @@ func TestFormatDetection(t *testing.T) {
 		{".env", FormatEnv},
 		{"config.env", FormatEnv},
 		{"app.json", FormatJSON},
+		{"config.toml", FormatTOML},
+		{"legacy.ini", FormatINI},
+		{"app.properties", FormatProperties},
 		{"config.yml", FormatYAML},
 		{"app.yaml", FormatYAML},
 		{"unknown.txt", FormatEnv}, // Default to env
@@ -318,3 +321,238 @@ func TestReload(t *testing.T) {
 		t.Errorf("Expected TEST_VALUE=updated after reload, got %s", config.Str("TEST_VALUE"))
 	}
 }
+
+func TestIsolatedConfig(t *testing.T) {
+	err := createTestFile("isolated_a.env", "ISOLATED_VALUE=a\nISOLATED_ONLY_A=yes\n")
+	if err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	defer cleanupTestFile("isolated_a.env")
+
+	err = createTestFile("isolated_b.json", `{"isolated": {"value": "b"}}`)
+	if err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	defer cleanupTestFile("isolated_b.json")
+
+	configA := NewWithOptions("isolated_a.env", WithIsolated())
+	configB := NewWithOptions("isolated_b.json", WithIsolated())
+
+	if configA.Str("ISOLATED_VALUE") != "a" {
+		t.Errorf("Expected ISOLATED_VALUE=a, got %s", configA.Str("ISOLATED_VALUE"))
+	}
+	if configB.Str("ISOLATED_VALUE") != "b" {
+		t.Errorf("Expected ISOLATED_VALUE=b, got %s", configB.Str("ISOLATED_VALUE"))
+	}
+	if configB.Str("ISOLATED_ONLY_A") != "" {
+		t.Errorf("Expected ISOLATED_ONLY_A to be unset in configB, got %s", configB.Str("ISOLATED_ONLY_A"))
+	}
+	if _, ok := os.LookupEnv("ISOLATED_VALUE"); ok {
+		t.Errorf("Expected isolated config not to modify the process environment")
+	}
+
+	// Fallback to the process environment for keys the config did not load
+	os.Setenv("ISOLATED_FALLBACK", "from-env")
+	defer os.Unsetenv("ISOLATED_FALLBACK")
+
+	if configA.Str("ISOLATED_FALLBACK") != "" {
+		t.Errorf("Expected isolated config without fallback to ignore the environment")
+	}
+	withFallback := NewWithOptions("isolated_a.env", WithIsolated(), WithEnvFallback())
+	if withFallback.Str("ISOLATED_FALLBACK") != "from-env" {
+		t.Errorf("Expected ISOLATED_FALLBACK=from-env, got %s", withFallback.Str("ISOLATED_FALLBACK"))
+	}
+
+	// Explicit export
+	if err := configA.Export(); err != nil {
+		t.Fatalf("Failed to export config: %v", err)
+	}
+	defer os.Unsetenv("ISOLATED_VALUE")
+	defer os.Unsetenv("ISOLATED_ONLY_A")
+
+	if os.Getenv("ISOLATED_VALUE") != "a" {
+		t.Errorf("Expected exported ISOLATED_VALUE=a, got %s", os.Getenv("ISOLATED_VALUE"))
+	}
+}
+
+func TestTOMLFormat(t *testing.T) {
+	tomlContent := `title = "TOML App"
+
+[database]
+host = "localhost"
+port = 5432
+enabled = true
+started = 2024-01-02T15:04:05Z
+
+[database.pool]
+max = 20
+
+[app]
+features = ["auth", "logging"]
+
+[[servers]]
+name = "alpha"
+ip = "10.0.0.1"
+
+[[servers]]
+name = "beta"
+ip = "10.0.0.2"
+`
+	err := createTestFile("test.toml", tomlContent)
+	if err != nil {
+		t.Fatalf("Failed to create test toml file: %v", err)
+	}
+	defer cleanupTestFile("test.toml")
+
+	config := NewWithOptions("test.toml", WithIsolated())
+
+	tests := map[string]string{
+		"TITLE":             "TOML App",
+		"DATABASE_HOST":     "localhost",
+		"DATABASE_PORT":     "5432",
+		"DATABASE_ENABLED":  "true",
+		"DATABASE_STARTED":  "2024-01-02T15:04:05Z",
+		"DATABASE_POOL_MAX": "20",
+		"APP_FEATURES":      "auth,logging",
+		"SERVERS_0_NAME":    "alpha",
+		"SERVERS_1_IP":      "10.0.0.2",
+	}
+	for key, expected := range tests {
+		if value := config.Str(key); value != expected {
+			t.Errorf("Expected %s=%s, got %s", key, expected, value)
+		}
+	}
+
+	// SetFile and Reload switch formats
+	err = createTestFile("test_switch.env", "TITLE=from-env\n")
+	if err != nil {
+		t.Fatalf("Failed to create test env file: %v", err)
+	}
+	defer cleanupTestFile("test_switch.env")
+
+	if err := config.SetFile("test_switch.env"); err != nil {
+		t.Fatalf("Failed to set file: %v", err)
+	}
+	if config.Str("TITLE") != "from-env" {
+		t.Errorf("Expected TITLE=from-env, got %s", config.Str("TITLE"))
+	}
+	if err := config.SetFile("test.toml"); err != nil {
+		t.Fatalf("Failed to set file: %v", err)
+	}
+	if config.Str("TITLE") != "TOML App" {
+		t.Errorf("Expected TITLE=TOML App, got %s", config.Str("TITLE"))
+	}
+
+	// Global loader
+	if err := LoadConfigFile("test.toml"); err != nil {
+		t.Fatalf("Failed to load toml file: %v", err)
+	}
+	if Int("DATABASE_POOL_MAX") != 20 {
+		t.Errorf("Expected DATABASE_POOL_MAX=20, got %d", Int("DATABASE_POOL_MAX"))
+	}
+}
+
+func TestINIFormat(t *testing.T) {
+	iniContent := `; Legacy service config
+app_name = Legacy App
+
+[database]
+host = db.legacy
+port : 3306
+password = "p;ss # word"
+timeout = 30 ; seconds
+user = "admin" ; quoted value with a comment
+role = 'rw;ro';comment
+
+[database.pool]
+max=10
+`
+	err := createTestFile("test.ini", iniContent)
+	if err != nil {
+		t.Fatalf("Failed to create test ini file: %v", err)
+	}
+	defer cleanupTestFile("test.ini")
+
+	config := NewWithOptions("test.ini", WithIsolated())
+
+	tests := map[string]string{
+		"APP_NAME":          "Legacy App",
+		"DATABASE_HOST":     "db.legacy",
+		"DATABASE_PASSWORD": "p;ss # word",
+		"DATABASE_USER":     "admin",
+		"DATABASE_ROLE":     "rw;ro",
+		"DATABASE_POOL_MAX": "10",
+	}
+	for key, expected := range tests {
+		if value := config.Str(key); value != expected {
+			t.Errorf("Expected %s=%s, got %s", key, expected, value)
+		}
+	}
+	if config.Int("DATABASE_PORT") != 3306 {
+		t.Errorf("Expected DATABASE_PORT=3306, got %d", config.Int("DATABASE_PORT"))
+	}
+	if config.Int("DATABASE_TIMEOUT") != 30 {
+		t.Errorf("Expected DATABASE_TIMEOUT=30, got %d", config.Int("DATABASE_TIMEOUT"))
+	}
+
+	// Section keys are exported by environment name
+	exported := New("test.ini")
+	defer exported.SetFile("")
+	if os.Getenv("DATABASE_HOST") != "db.legacy" || Str("DATABASE_HOST") != "db.legacy" {
+		t.Errorf("Expected DATABASE_HOST=db.legacy in the environment, got %q", os.Getenv("DATABASE_HOST"))
+	}
+	if _, ok := os.LookupEnv("database.host"); ok {
+		t.Error("Expected database.host not to be exported")
+	}
+}
+
+func TestPropertiesFormat(t *testing.T) {
+	propertiesContent := `# Java properties
+! also a comment
+database.host=db.props
+database.port: 5432
+app.name   Props App
+app.greeting = Hello \
+    World
+app.path = C:\\data\tnext
+app.unicode = caf\u00e9 \ud83d\ude00
+key\=with\:separators = escaped
+empty.value
+`
+	err := createTestFile("test.properties", propertiesContent)
+	if err != nil {
+		t.Fatalf("Failed to create test properties file: %v", err)
+	}
+	defer cleanupTestFile("test.properties")
+
+	config := NewWithOptions("test.properties", WithIsolated())
+
+	tests := map[string]string{
+		"DATABASE_HOST":       "db.props",
+		"DATABASE_PORT":       "5432",
+		"APP_NAME":            "Props App",
+		"APP_GREETING":        "Hello World",
+		"APP_PATH":            "C:\\data\tnext",
+		"APP_UNICODE":         "caf\u00e9 \U0001F600",
+		"KEY=WITH:SEPARATORS": "escaped",
+	}
+	for key, expected := range tests {
+		if value := config.Str(key); value != expected {
+			t.Errorf("Expected %s=%q, got %q", key, expected, value)
+		}
+	}
+	if origin, ok := config.Origin("EMPTY_VALUE"); !ok || origin != "test.properties" {
+		t.Errorf("Expected EMPTY_VALUE to be loaded with an empty value")
+	}
+
+	// Dotted keys are exported by environment name
+	for _, key := range []string{"DATABASE_HOST", "DATABASE_PORT", "APP_NAME", "APP_GREETING", "APP_PATH", "APP_UNICODE", "KEY=WITH:SEPARATORS", "EMPTY_VALUE"} {
+		defer os.Unsetenv(key)
+	}
+	if err := LoadConfigFile("test.properties"); err != nil {
+		t.Fatalf("Failed to load properties file: %v", err)
+	}
+	if os.Getenv("DATABASE_HOST") != "db.props" || Str("DATABASE_HOST") != "db.props" || Int("DATABASE_PORT") != 5432 {
+		t.Errorf("Expected DATABASE_HOST=db.props in the environment, got %q", os.Getenv("DATABASE_HOST"))
+	}
+}
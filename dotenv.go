@@ -0,0 +1,150 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Dotenv is a Source that loads the standard dotenv cascade from Dir:
+//
+//	.env                 shared defaults
+//	.env.local           local overrides, skipped in the test environment
+//	.env.<env>           environment specific defaults
+//	.env.<env>.local     local environment specific overrides
+//
+// Later files override earlier ones. The environment name is taken from Env,
+// or from the first variable in EnvVars that is set.
+type Dotenv struct {
+	Dir     string   // Directory containing the files, defaults to "."
+	EnvVars []string // Variables naming the environment, defaults to APP_ENV and GO_ENV
+	Env     string   // Explicit environment name, overrides EnvVars
+	TestEnv string   // Environment in which .env.local is skipped, defaults to "test"
+
+	mu    sync.Mutex // Guards found, which a watched Config's reload writes
+	found []string
+}
+
+// Name identifies the cascade when reporting which source provided a value
+func (d *Dotenv) Name() string {
+	return "dotenv"
+}
+
+// Load reads every file of the cascade that exists
+func (d *Dotenv) Load() (map[string]interface{}, error) {
+	config, err := d.load(false)
+	if err != nil {
+		return nil, err
+	}
+	return plainValues(config), nil
+}
+
+func (d *Dotenv) loadWith(opts loadOptions) (map[string]interface{}, error) {
+	return d.load(opts.strict)
+}
+
+// load reads the cascade, rejecting malformed lines in strict mode
+func (d *Dotenv) load(strict bool) (map[string]interface{}, error) {
+	config := make(map[string]interface{})
+	var found []string
+	for _, path := range d.Paths() {
+		if _, err := os.Stat(path); err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to open env file %s: %w", path, err)
+		}
+
+		values, err := loadConfigFileAs(path, FormatEnv, loadOptions{strict: strict})
+		if err != nil {
+			return nil, err
+		}
+		for key, value := range values {
+			config[key] = value
+		}
+		found = append(found, path)
+	}
+
+	d.mu.Lock()
+	d.found = found
+	d.mu.Unlock()
+	return config, nil
+}
+
+// Files returns the files found by the last Load in the order they were applied
+func (d *Dotenv) Files() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]string(nil), d.found...)
+}
+
+// Environment returns the resolved environment name, or "" if none is set
+func (d *Dotenv) Environment() string {
+	if d.Env != "" {
+		return d.Env
+	}
+	envVars := d.EnvVars
+	if len(envVars) == 0 {
+		envVars = []string{"APP_ENV", "GO_ENV"}
+	}
+	for _, name := range envVars {
+		if value := os.Getenv(name); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// Paths returns the candidate files of the cascade in order of increasing precedence
+func (d *Dotenv) Paths() []string {
+	dir := d.Dir
+	if dir == "" {
+		dir = "."
+	}
+	testEnv := d.TestEnv
+	if testEnv == "" {
+		testEnv = "test"
+	}
+
+	env := d.Environment()
+	names := []string{".env"}
+	if env != testEnv {
+		names = append(names, ".env.local")
+	}
+	if env != "" {
+		names = append(names, ".env."+env, ".env."+env+".local")
+	}
+
+	paths := make([]string, len(names))
+	for i, name := range names {
+		paths[i] = filepath.Join(dir, name)
+	}
+	return paths
+}
+
+// LoadDotenv loads the dotenv cascade from dir (default ".") into environment variables
+// Variables that are already set in the environment are left untouched, as with
+// other dotenv implementations. It returns the files that were found.
+func LoadDotenv(dir ...string) ([]string, error) {
+	cascade := &Dotenv{}
+	if len(dir) > 0 {
+		cascade.Dir = dir[0]
+	}
+
+	config, err := cascade.load(false) // Keeps literal values out of interpolation
+	if err != nil {
+		return nil, err
+	}
+	if err := interpolate(config, os.LookupEnv); err != nil {
+		return nil, err
+	}
+
+	// Set environment variables, .env keys are used as-is
+	for key, value := range config {
+		if _, ok := os.LookupEnv(key); !ok {
+			os.Setenv(key, formatValue(value))
+		}
+	}
+	return cascade.Files(), nil
+}
@@ -0,0 +1,105 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestDotenvCascade(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		".env":                  "CASCADE_A=env\nCASCADE_B=env\nCASCADE_C=env\nCASCADE_D=env\n",
+		".env.local":            "CASCADE_B=local\nCASCADE_C=local\n",
+		".env.staging":          "CASCADE_C=staging\nCASCADE_D=staging\n",
+		".env.staging.local":    "CASCADE_D=staging-local\n",
+		".env.test":             "CASCADE_C=test\n",
+		".env.production.local": "CASCADE_A=production\n",
+	}
+	for name, content := range files {
+		if err := createTestFile(filepath.Join(dir, name), content); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+
+	cascade := &Dotenv{Dir: dir, EnvVars: []string{"CASCADE_ENV"}}
+	os.Setenv("CASCADE_ENV", "staging")
+	defer os.Unsetenv("CASCADE_ENV")
+
+	config := NewWithOptions("", WithIsolated(), WithSources(cascade))
+
+	expected := map[string]string{
+		"CASCADE_A": "env",
+		"CASCADE_B": "local",
+		"CASCADE_C": "staging",
+		"CASCADE_D": "staging-local",
+	}
+	for key, value := range expected {
+		if config.Str(key) != value {
+			t.Errorf("Expected %s=%s, got %s", key, value, config.Str(key))
+		}
+	}
+
+	found := []string{
+		filepath.Join(dir, ".env"),
+		filepath.Join(dir, ".env.local"),
+		filepath.Join(dir, ".env.staging"),
+		filepath.Join(dir, ".env.staging.local"),
+	}
+	if !reflect.DeepEqual(cascade.Files(), found) {
+		t.Errorf("Expected files %v, got %v", found, cascade.Files())
+	}
+
+	// The test environment skips .env.local
+	os.Setenv("CASCADE_ENV", "test")
+	if err := config.Reload(); err != nil {
+		t.Fatalf("Failed to reload config: %v", err)
+	}
+	if config.Str("CASCADE_B") != "env" {
+		t.Errorf("Expected CASCADE_B=env in test environment, got %s", config.Str("CASCADE_B"))
+	}
+	if config.Str("CASCADE_C") != "test" {
+		t.Errorf("Expected CASCADE_C=test in test environment, got %s", config.Str("CASCADE_C"))
+	}
+}
+
+func TestLoadDotenvKeepsEnvironment(t *testing.T) {
+	dir := t.TempDir()
+	err := createTestFile(filepath.Join(dir, ".env"), "DOTENV_SET=file\nDOTENV_KEEP=file\ndotenv_lower=v1\nDOTENV_LITERAL='${DOTENV_SET}'\n")
+	if err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	os.Setenv("DOTENV_KEEP", "shell")
+	defer os.Unsetenv("DOTENV_KEEP")
+	defer os.Unsetenv("DOTENV_SET")
+	defer os.Unsetenv("dotenv_lower")
+	defer os.Unsetenv("DOTENV_LOWER")
+	defer os.Unsetenv("DOTENV_LITERAL")
+
+	found, err := LoadDotenv(dir)
+	if err != nil {
+		t.Fatalf("Failed to load dotenv: %v", err)
+	}
+	if len(found) != 1 {
+		t.Errorf("Expected 1 file found, got %v", found)
+	}
+	if Str("DOTENV_SET") != "file" {
+		t.Errorf("Expected DOTENV_SET=file, got %s", Str("DOTENV_SET"))
+	}
+	if Str("DOTENV_KEEP") != "shell" {
+		t.Errorf("Expected DOTENV_KEEP=shell, got %s", Str("DOTENV_KEEP"))
+	}
+
+	// Keys are exported as written, like LoadEnvFile
+	if Str("dotenv_lower") != "v1" {
+		t.Errorf("Expected dotenv_lower=v1, got %s", Str("dotenv_lower"))
+	}
+	if _, ok := os.LookupEnv("DOTENV_LOWER"); ok {
+		t.Error("Expected DOTENV_LOWER not to be exported")
+	}
+	if Str("DOTENV_LITERAL") != "${DOTENV_SET}" {
+		t.Errorf("Expected single quotes to stay literal, got %s", Str("DOTENV_LITERAL"))
+	}
+}
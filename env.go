@@ -1,34 +1,78 @@
 package config
 
 import (
-	"bufio"
+	"errors"
 	"fmt"
 	"os"
-	"strconv"
+	"slices"
 	"strings"
+	"sync"
+	"sync/atomic"
 )
 
+// Config is safe for concurrent use. Loaded values are kept in an immutable
+// state that each reload replaces atomically, so readers never lock.
 type Config struct {
-	configFile   string
-	loaded       bool
-	format       ConfigFormat
-	loadedConfig map[string]interface{} // Keep track of loaded config for reload
+	state atomic.Pointer[loadState] // Values of the last successful load
+
+	mu          sync.Mutex // Serializes loads and guards the fields below
+	configFile  string
+	loaded      bool
+	format      ConfigFormat
+	exported    map[string]priorEnv // Environment values replaced by this Config
+	subscribers []func(ChangeSet)   // Functions added with OnChange
+	required    []string            // Keys added with Require or WithRequired
+	rules       []keyRules          // Rules added with AddRule or WithRule
+
+	// Set by options before the first load and never changed afterwards
+	layers        []Source      // Additional sources layered on top of configFile
+	strict        bool          // Reject malformed lines and duplicate keys
+	isolated      bool          // Keep values out of the process environment
+	envFallback   bool          // Isolated lookups fall back to the process environment
+	frozen        bool          // Snapshot that cannot be reloaded
+	root          *Config       // Config a view returned by Sub reads from
+	prefix        string        // Environment name prefix of a view's keys, e.g. DATABASE_
+	watch         watchSettings // Settings used by Watch
+	timeLayouts   []string      // Layouts tried by Time after RFC3339
+	listSeparator string        // Separator used by StrSlice, IntSlice and StringMap
+	structs       []interface{} // Structs added with WithStruct, checked on every load
+	schema        *Schema       // Schema added with WithSchema, checked against structured files
+}
+
+// errSnapshot is returned when reloading a Config returned by Snapshot
+var errSnapshot = errors.New("cannot reload a config snapshot")
+
+// priorEnv remembers an environment variable as it was before a Config exported over it
+type priorEnv struct {
+	value string
+	set   bool
 }
 
 // New creates a new Config instance with optional config file path
 // If no file path is provided, it defaults to ".env"
-// Supports .env, .json, .yml, .yaml formats
+// Supports .env, .json, .yml, .yaml, .toml, .ini, .properties formats
 func New(configFile ...string) *Config {
 	file := ".env"
 	if len(configFile) > 0 {
 		file = configFile[0]
 	}
 
+	return NewWithOptions(file)
+}
+
+// NewWithOptions creates a new Config instance for the given config file and applies options
+// An empty configFile loads only the sources added with WithSources
+func NewWithOptions(configFile string, opts ...Option) *Config {
 	config := &Config{
-		configFile:   file,
-		loaded:       false,
-		format:       detectFormat(file),
-		loadedConfig: make(map[string]interface{}),
+		configFile: configFile,
+		loaded:     false,
+		format:     detectFormat(configFile),
+		exported:   make(map[string]priorEnv),
+	}
+	config.state.Store(newLoadState())
+
+	for _, opt := range opts {
+		opt(config)
 	}
 
 	// Auto-load the config file
@@ -37,26 +81,152 @@ func New(configFile ...string) *Config {
 	return config
 }
 
-// Load loads the config file into environment variables
+// Load loads the config file and any additional sources into environment variables
+// Later sources override values from earlier ones
 func (c *Config) Load() error {
+	if c.root != nil {
+		return c.root.Load()
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.load()
+}
+
+// load loads every source unless the Config is already loaded, c.mu must be held
+// A loaded Config is only checked against the keys marked with Require.
+func (c *Config) load() error {
 	if c.loaded {
-		return nil // Already loaded
+		return c.validate(c.state.Load())
+	}
+
+	state, err := c.build()
+	if err != nil {
+		return err
+	}
+	c.apply(state)
+	return nil
+}
+
+// loadState holds the merged result of loading every source
+// A state is never modified once it has been stored in a Config.
+type loadState struct {
+	config  map[string]interface{} // Flattened config keys and values
+	typed   map[string]interface{} // Values as decoded, keyed by environment variable name
+	values  map[string]string      // Values in string form, keyed by environment variable name
+	origins map[string]string      // Name of the source that provided each value
+	names   map[string]string      // Name each value is exported under, keyed by environment variable name
+}
+
+// newLoadState returns an empty state
+func newLoadState() *loadState {
+	return &loadState{
+		config:  make(map[string]interface{}),
+		typed:   make(map[string]interface{}),
+		values:  make(map[string]string),
+		origins: make(map[string]string),
+		names:   make(map[string]string),
+	}
+}
+
+// exports returns the values as they are written to the process environment
+func (s *loadState) exports() map[string]string {
+	exports := make(map[string]string, len(s.values))
+	for key, value := range s.values {
+		exports[s.names[key]] = value
+	}
+	return exports
+}
+
+// build loads, merges and validates every source without changing the Config, c.mu must be held
+// Values this Config exported are ignored, so a rebuild sees the environment as it was before loading
+func (c *Config) build() (*loadState, error) {
+	state := newLoadState()
+	paths := make(map[string]string) // Path of each environment name in state.config
+	for i, source := range c.sources() {
+		config, err := c.loadSource(source, i == 0 && c.configFile != "")
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := source.(*envSource); ok {
+			// Real environment values are taken literally
+			c.dropExports(config)
+		} else {
+			earlier := func(key string) (string, bool) {
+				if value, ok := state.values[toEnvKey(key)]; ok {
+					return value, true
+				}
+				return c.lookupEnv(key)
+			}
+			if err := interpolate(config, earlier); err != nil {
+				return nil, err
+			}
+		}
+
+		for key, value := range config {
+			envKey := toEnvKey(key)
+			if previous, ok := paths[envKey]; ok && previous != key {
+				delete(state.config, previous) // Overridden under another spelling, e.g. DB_HOST over db.host
+			}
+			paths[envKey] = key
+			state.config[key] = value
+			state.values[envKey] = formatValue(value)
+			state.typed[envKey] = value
+			state.origins[envKey] = source.Name()
+			state.names[envKey] = exportName(source, key)
+		}
+	}
+	if err := c.validate(state); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// exportName returns the environment variable a key of source is exported as
+// Keys of .env files and the environment are exported as written, like
+// LoadEnvFile does. Other keys are exported as DB_HOST, so database.host from
+// an INI section or a nested JSON object is read as DATABASE_HOST.
+func exportName(source Source, key string) string {
+	switch s := source.(type) {
+	case *envSource, *Dotenv:
+		return key
+	case *fileSource:
+		if s.format == FormatEnv {
+			return key
+		}
 	}
+	return toEnvKey(key)
+}
+
+// apply makes a loaded state current, c.mu must be held
+func (c *Config) apply(state *loadState) {
+	c.state.Store(state)
+	c.exportValues(state.exports())
+	c.loaded = true
+}
 
-	var err error
-	switch c.format {
-	case FormatEnv:
-		err = c.loadEnvFile(c.configFile)
-	case FormatJSON, FormatYAML:
-		err = c.loadStructuredFile(c.configFile)
-	default:
-		err = fmt.Errorf("unsupported config format for file: %s", c.configFile)
+// lookupEnv reads the process environment as it was before this Config exported to it
+func (c *Config) lookupEnv(key string) (string, bool) {
+	if prior, ok := c.exported[key]; ok {
+		return prior.value, prior.set
 	}
+	return os.LookupEnv(key)
+}
 
-	if err == nil {
-		c.loaded = true
+// dropExports replaces values this Config exported in an environment snapshot with their prior values
+func (c *Config) dropExports(config map[string]interface{}) {
+	for key := range config {
+		prior, ok := c.exported[key]
+		if !ok {
+			continue
+		}
+		if prior.set {
+			config[key] = prior.value
+		} else {
+			delete(config, key)
+		}
 	}
-	return err
 }
 
 // MustLoad loads the config file and panics if there's an error
@@ -68,7 +238,7 @@ func (c *Config) MustLoad() {
 
 // Str retrieves a string environment variable with optional default value
 func (c *Config) Str(key string, defaultValue ...string) string {
-	if value := os.Getenv(key); value != "" {
+	if value, _ := c.lookup(key); value != "" {
 		return value
 	}
 	if len(defaultValue) > 0 {
@@ -79,155 +249,229 @@ func (c *Config) Str(key string, defaultValue ...string) string {
 
 // Int retrieves an integer environment variable with optional default value
 func (c *Config) Int(key string, defaultValue ...int) int {
-	if value := os.Getenv(key); value != "" {
-		if intValue, err := strconv.Atoi(value); err == nil {
-			return intValue
-		}
-	}
-	if len(defaultValue) > 0 {
-		return defaultValue[0]
-	}
-	return 0
+	value, _ := c.lookup(key)
+	return typedValue(value, parseInt, defaultValue)
 }
 
 // Bool retrieves a boolean environment variable with optional default value
 func (c *Config) Bool(key string, defaultValue ...bool) bool {
-	if value := os.Getenv(key); value != "" {
-		lowerValue := strings.ToLower(strings.TrimSpace(value))
-		switch lowerValue {
-		case "true", "1", "yes", "on":
-			return true
-		case "false", "0", "no", "off":
-			return false
-		}
-	}
-	if len(defaultValue) > 0 {
-		return defaultValue[0]
-	}
-	return false
+	value, _ := c.lookup(key)
+	return typedValue(value, parseBool, defaultValue)
 }
 
 // All returns all environment variables as a map
+// Use LoadedAll for only the values this Config loaded.
 func (c *Config) All() map[string]string {
 	return All()
 }
 
-// Reload reloads the config file
-func (c *Config) Reload() error {
-	// Clear previously loaded config
-	if len(c.loadedConfig) > 0 {
-		clearEnvironmentVariables(c.loadedConfig)
-		c.loadedConfig = make(map[string]interface{})
+// Origin returns the name of the source whose value won for key
+// The name is the file path for file sources, "env" or "defaults" for the others
+func (c *Config) Origin(key string) (string, bool) {
+	if c.root != nil {
+		return c.root.Origin(c.rootKey(key))
 	}
+	origin, ok := c.state.Load().origins[toEnvKey(key)]
+	return origin, ok
+}
 
-	c.loaded = false
-	return c.Load()
+// Export writes the loaded values to the process environment
+// This is only needed for isolated configs, other configs export while loading
+func (c *Config) Export() error {
+	if c.root != nil {
+		return c.root.Export()
+	}
+	for key, value := range c.state.Load().exports() {
+		if err := os.Setenv(key, value); err != nil {
+			return fmt.Errorf("failed to export %s: %w", key, err)
+		}
+	}
+	return nil
 }
 
-// SetFile changes the config file path and reloads
-func (c *Config) SetFile(configFile string) error {
-	// Clear previously loaded config
-	if len(c.loadedConfig) > 0 {
-		clearEnvironmentVariables(c.loadedConfig)
-		c.loadedConfig = make(map[string]interface{})
+// Snapshot returns a read-only Config holding the values loaded now
+// Later reloads do not affect the snapshot, so reading several keys from it
+// always gives a consistent view. A snapshot reads its own values first and
+// only falls back to the process environment for keys it did not load, if the
+// Config reads the environment. Reload, SetFile and Watch fail on a snapshot.
+func (c *Config) Snapshot() *Config {
+	if c.root != nil {
+		return c.root.Snapshot().Sub(strings.TrimSuffix(c.prefix, "_"))
 	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	snapshot := &Config{
+		configFile:    c.configFile,
+		loaded:        true,
+		format:        c.format,
+		exported:      make(map[string]priorEnv),
+		layers:        c.layers,
+		strict:        c.strict,
+		timeLayouts:   c.timeLayouts,
+		listSeparator: c.listSeparator,
+		required:      slices.Clone(c.required),
+		rules:         slices.Clone(c.rules),
+		structs:       c.structs,
+		schema:        c.schema,
+		isolated:      true,
+		envFallback:   !c.isolated || c.envFallback,
+		frozen:        true,
+	}
+	snapshot.state.Store(c.state.Load())
+	return snapshot
+}
 
-	c.configFile = configFile
-	c.format = detectFormat(configFile)
-	c.loaded = false
-	return c.Load()
+// Reload re-reads the config file and every additional source
+// The new values are loaded completely before they replace the current ones,
+// so if any source fails the Config keeps its previous values and the error
+// is returned. Readers see either the previous or the new values, never a mix.
+// Subscribers added with OnChange are notified of the values that changed.
+func (c *Config) Reload() error {
+	if c.root != nil {
+		return c.root.Reload()
+	}
+	return c.update(func() error {
+		if c.frozen {
+			return errSnapshot
+		}
+		state, err := c.build()
+		if err != nil {
+			return err
+		}
+		c.swap(state)
+		return nil
+	})
 }
 
-// loadStructuredFile loads JSON/YAML config files
-func (c *Config) loadStructuredFile(filePath string) error {
-	config, err := loadConfigFile(filePath)
-	if err != nil {
-		return err
+// SetFile changes the config file path and reloads
+// If the new file fails to load the Config keeps its previous file and values.
+// Subscribers added with OnChange are notified of the values that changed.
+func (c *Config) SetFile(configFile string) error {
+	if c.root != nil {
+		return c.root.SetFile(configFile)
 	}
+	return c.update(func() error {
+		if c.frozen {
+			return errSnapshot
+		}
+		previousFile, previousFormat := c.configFile, c.format
+		c.configFile = configFile
+		c.format = detectFormat(configFile)
 
-	// Store loaded config for reload functionality
-	c.loadedConfig = config
+		state, err := c.build()
+		if err != nil {
+			c.configFile, c.format = previousFile, previousFormat
+			return err
+		}
+		c.swap(state)
+		return nil
+	})
+}
 
-	// Set environment variables from config
-	setEnvironmentVariables(config)
-	return nil
+// sources returns the layers to load in order of increasing precedence
+func (c *Config) sources() []Source {
+	var sources []Source
+	if c.configFile != "" {
+		sources = append(sources, &fileSource{path: c.configFile, format: c.format})
+	}
+	return append(sources, c.layers...)
 }
 
-// loadEnvFile is the internal method to load .env file (backward compatibility)
-func (c *Config) loadEnvFile(filePath string) error {
-	file, err := os.Open(filePath)
-	if err != nil {
-		// If file doesn't exist, ignore silently
-		if os.IsNotExist(err) {
-			return nil
+// loadSource loads a single layer, applying strict mode when the source supports it
+// The schema only applies to the primary config file, as layers on top of it
+// may override a few keys and would fail keywords like required on their own.
+func (c *Config) loadSource(source Source, primary bool) (map[string]interface{}, error) {
+	if s, ok := source.(optionSource); ok {
+		opts := loadOptions{strict: c.strict}
+		if primary {
+			opts.schema = c.schema
 		}
-		return fmt.Errorf("failed to open env file %s: %w", filePath, err)
+		return s.loadWith(opts)
 	}
-	defer file.Close()
+	return source.Load()
+}
 
-	config := make(map[string]interface{})
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
+// lookup returns the value of key from the process environment or the Config's store
+func (c *Config) lookup(key string) (string, bool) {
+	if c.root != nil {
+		return c.root.lookup(c.rootKey(key))
+	}
+	if !c.isolated {
+		if value, ok := os.LookupEnv(key); ok {
+			return value, true
+		}
+	}
+	if value, ok := c.state.Load().values[toEnvKey(key)]; ok {
+		return value, true
+	}
+	if c.isolated && c.envFallback {
+		return os.LookupEnv(key)
+	}
+	return "", false
+}
 
-		// Skip empty lines and comments
-		if line == "" || strings.HasPrefix(line, "#") {
-			continue
+// exportValues exports loaded values unless the Config is isolated, c.mu must be held
+func (c *Config) exportValues(values map[string]string) {
+	if c.isolated {
+		return
+	}
+	for key, value := range values {
+		if _, ok := c.exported[key]; !ok {
+			prior, set := os.LookupEnv(key)
+			c.exported[key] = priorEnv{value: prior, set: set}
 		}
+		os.Setenv(key, value)
+	}
+}
 
-		// Parse key=value
-		parts := strings.SplitN(line, "=", 2)
-		if len(parts) != 2 {
+// swap replaces the current values with a loaded state, c.mu must be held
+// Keys loaded in both states are overwritten in place rather than unset first,
+// so the process environment never lacks a key that stays loaded.
+func (c *Config) swap(state *loadState) {
+	exports := state.exports()
+	for key, prior := range c.exported {
+		if _, ok := exports[key]; ok {
 			continue
 		}
-
-		key := strings.TrimSpace(parts[0])
-		value := strings.TrimSpace(parts[1])
-
-		// Remove quotes if present
-		if len(value) >= 2 {
-			if (value[0] == '"' && value[len(value)-1] == '"') ||
-				(value[0] == '\'' && value[len(value)-1] == '\'') {
-				value = value[1 : len(value)-1]
-			}
+		if prior.set {
+			os.Setenv(key, prior.value)
+		} else {
+			os.Unsetenv(key)
 		}
-
-		// Store in config for reload functionality
-		config[key] = value
-
-		// Set environment variable
-		os.Setenv(key, value)
+		delete(c.exported, key)
 	}
-
-	// Store loaded config for reload functionality
-	c.loadedConfig = config
-	return scanner.Err()
+	c.apply(state)
 }
 
 // Global functions for backward compatibility
 
-// LoadConfigFile loads configuration from various file formats (.env, .json, .yml, .yaml)
+// LoadConfigFile loads configuration from various file formats (.env, .json, .yml, .yaml, .toml, .ini, .properties)
+// Extensions added with RegisterFormat are supported too
 func LoadConfigFile(filePath ...string) error {
 	configFile := ".env"
 	if len(filePath) > 0 {
 		configFile = filePath[0]
 	}
 
-	format := detectFormat(configFile)
+	return LoadConfigFileAs(configFile, detectFormat(configFile))
+}
 
-	switch format {
-	case FormatEnv:
+// LoadConfigFileAs loads a configuration file in the given format regardless of its extension
+func LoadConfigFileAs(configFile string, format ConfigFormat) error {
+	if format == FormatEnv {
 		return LoadEnvFile(configFile)
-	case FormatJSON, FormatYAML:
-		config, err := loadConfigFile(configFile)
-		if err != nil {
-			return err
-		}
-		setEnvironmentVariables(config)
-		return nil
-	default:
-		return fmt.Errorf("unsupported config format for file: %s", configFile)
 	}
+
+	config, err := loadConfigFileAs(configFile, format, loadOptions{})
+	if err != nil {
+		return err
+	}
+	if err := interpolate(config, os.LookupEnv); err != nil {
+		return err
+	}
+	setEnvironmentVariables(config)
+	return nil
 }
 
 // MustLoadConfigFile loads configuration file and panics if there's an error
@@ -244,7 +488,7 @@ func LoadEnvFile(filePath ...string) error {
 		envFile = filePath[0]
 	}
 
-	file, err := os.Open(envFile)
+	data, err := os.ReadFile(envFile)
 	if err != nil {
 		// If file doesn't exist, ignore silently
 		if os.IsNotExist(err) {
@@ -252,39 +496,20 @@ func LoadEnvFile(filePath ...string) error {
 		}
 		return fmt.Errorf("failed to open env file %s: %w", envFile, err)
 	}
-	defer file.Close()
 
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-
-		// Skip empty lines and comments
-		if line == "" || strings.HasPrefix(line, "#") {
-			continue
-		}
-
-		// Parse key=value
-		parts := strings.SplitN(line, "=", 2)
-		if len(parts) != 2 {
-			continue
-		}
-
-		key := strings.TrimSpace(parts[0])
-		value := strings.TrimSpace(parts[1])
-
-		// Remove quotes if present
-		if len(value) >= 2 {
-			if (value[0] == '"' && value[len(value)-1] == '"') ||
-				(value[0] == '\'' && value[len(value)-1] == '\'') {
-				value = value[1 : len(value)-1]
-			}
-		}
-
-		// Set environment variable
-		os.Setenv(key, value)
+	config, err := loadEnvConfig(data, false)
+	if err != nil {
+		return withFile(err, envFile)
+	}
+	if err := interpolate(config, os.LookupEnv); err != nil {
+		return err
 	}
 
-	return scanner.Err()
+	// Set environment variables, .env keys are used as-is
+	for key, value := range config {
+		os.Setenv(key, formatValue(value))
+	}
+	return nil
 }
 
 // MustLoadEnvFile loads .env file and panics if there's an error (backward compatibility)
@@ -307,32 +532,12 @@ func Str(key string, defaultValue ...string) string {
 
 // Int retrieves an integer environment variable with optional default value
 func Int(key string, defaultValue ...int) int {
-	if value := os.Getenv(key); value != "" {
-		if intValue, err := strconv.Atoi(value); err == nil {
-			return intValue
-		}
-	}
-	if len(defaultValue) > 0 {
-		return defaultValue[0]
-	}
-	return 0
+	return typedValue(os.Getenv(key), parseInt, defaultValue)
 }
 
 // Bool retrieves a boolean environment variable with optional default value
 func Bool(key string, defaultValue ...bool) bool {
-	if value := os.Getenv(key); value != "" {
-		lowerValue := strings.ToLower(strings.TrimSpace(value))
-		switch lowerValue {
-		case "true", "1", "yes", "on":
-			return true
-		case "false", "0", "no", "off":
-			return false
-		}
-	}
-	if len(defaultValue) > 0 {
-		return defaultValue[0]
-	}
-	return false
+	return typedValue(os.Getenv(key), parseBool, defaultValue)
 }
 
 // All returns all environment variables as a map
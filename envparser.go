@@ -0,0 +1,299 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// envEntry is a single KEY=value assignment from a .env file
+type envEntry struct {
+	key     string
+	value   string
+	line    int
+	literal bool // The value must not be interpolated
+}
+
+// parseEnv parses .env data following the behaviour of common dotenv implementations:
+//
+//   - blank lines and lines starting with # are ignored
+//   - an optional "export " prefix before the key is ignored
+//   - unquoted values end at the line end or at a # preceded by whitespace
+//   - double-quoted values may span lines and support \n, \r, \t, \", \\ and \$
+//   - single-quoted and backtick values are literal and may span lines
+//
+// Single-quoted and backtick values, and double-quoted values whose only "$"
+// are escaped, are marked literal so interpolation leaves them untouched. A
+// double-quoted value mixing \$ with references keeps \$ as "$$", which
+// interpolation turns into "$". Malformed lines, including unterminated
+// quotes and text after a closing quote, are skipped unless strict is set, in
+// which case they and duplicate keys are reported as errors.
+// Errors are returned as *ParseError without the file name.
+func parseEnv(data []byte, strict bool) ([]envEntry, error) {
+	p := &envParser{src: strings.ReplaceAll(string(data), "\r\n", "\n"), line: 1}
+
+	var entries []envEntry
+	seen := make(map[string]int)
+	for !p.eof() {
+		p.skipBlank()
+		if p.eof() {
+			break
+		}
+
+		line := p.line
+		if p.peek() == '#' {
+			p.skipLine()
+			continue
+		}
+
+		key, err := p.readKey()
+		if err != nil {
+			if strict {
+				return nil, err
+			}
+			p.skipLine()
+			continue
+		}
+
+		value, literal, err := p.readValue()
+		if err != nil {
+			if strict {
+				return nil, err
+			}
+			p.skipLine()
+			continue
+		}
+
+		if first, ok := seen[key]; ok && strict {
+			return nil, &ParseError{
+				Line:   line,
+				Column: 1,
+				Text:   p.lineText(line),
+				Reason: fmt.Sprintf("duplicate key %s, first defined on line %d", key, first),
+			}
+		}
+		seen[key] = line
+		entries = append(entries, envEntry{key: key, value: value, line: line, literal: literal})
+	}
+	return entries, nil
+}
+
+// envParser is a cursor over .env source
+type envParser struct {
+	src       string
+	pos       int
+	line      int
+	lineStart int
+}
+
+func (p *envParser) eof() bool {
+	return p.pos >= len(p.src)
+}
+
+func (p *envParser) peek() byte {
+	return p.src[p.pos]
+}
+
+func (p *envParser) next() byte {
+	ch := p.src[p.pos]
+	p.pos++
+	if ch == '\n' {
+		p.line++
+		p.lineStart = p.pos
+	}
+	return ch
+}
+
+// errorAt returns a ParseError for the given position on the current line
+func (p *envParser) errorAt(pos int, reason string) *ParseError {
+	return &ParseError{
+		Line:   p.line,
+		Column: pos - p.lineStart + 1,
+		Text:   p.lineText(p.line),
+		Reason: reason,
+	}
+}
+
+// lineText returns the text of the given 1-based line
+func (p *envParser) lineText(line int) string {
+	lines := strings.SplitN(p.src, "\n", line+1)
+	if line-1 < len(lines) {
+		return lines[line-1]
+	}
+	return ""
+}
+
+// skipBlank skips whitespace including newlines
+func (p *envParser) skipBlank() {
+	for !p.eof() && strings.IndexByte(" \t\n\r", p.peek()) >= 0 {
+		p.next()
+	}
+}
+
+// skipSpaces skips whitespace on the current line
+func (p *envParser) skipSpaces() {
+	for !p.eof() && (p.peek() == ' ' || p.peek() == '\t') {
+		p.next()
+	}
+}
+
+// skipLine moves past the end of the current line
+func (p *envParser) skipLine() {
+	for !p.eof() {
+		if p.next() == '\n' {
+			return
+		}
+	}
+}
+
+// readKey reads "[export ]KEY=" and returns an error if the line is not an assignment
+func (p *envParser) readKey() (string, error) {
+	end := strings.IndexByte(p.src[p.pos:], '\n')
+	if end < 0 {
+		end = len(p.src) - p.pos
+	}
+	lineText := p.src[p.pos : p.pos+end]
+
+	eq := strings.IndexByte(lineText, '=')
+	if eq < 0 {
+		return "", p.errorAt(p.pos, "missing '=' in assignment")
+	}
+
+	key := strings.TrimSpace(lineText[:eq])
+	if rest, ok := strings.CutPrefix(key, "export "); ok {
+		key = strings.TrimSpace(rest)
+	}
+	if !isEnvKey(key) {
+		return "", p.errorAt(p.pos, fmt.Sprintf("invalid key %q", key))
+	}
+
+	p.pos += eq + 1
+	return key, nil
+}
+
+// readValue reads the value after "=" and consumes the rest of the line
+// It reports whether the value is literal. An unterminated quote leaves the
+// cursor at the opening quote, so the rest of the file can still be read
+// after skipping the line.
+func (p *envParser) readValue() (string, bool, error) {
+	p.skipSpaces()
+	if p.eof() {
+		return "", false, nil
+	}
+
+	var value string
+	var literal bool
+	var err error
+	start, opening := p.errorAt(p.pos, ""), *p
+	switch quote := p.peek(); quote {
+	case '"':
+		p.next()
+		value, literal, err = p.readDoubleQuoted()
+	case '\'', '`':
+		p.next()
+		value, err = p.readLiteral(quote)
+		literal = true
+	default:
+		return p.readUnquoted(), false, nil
+	}
+	if err != nil {
+		// Report unterminated values at the opening quote
+		*p = opening
+		start.Reason = err.Error()
+		return "", false, start
+	}
+
+	// Anything after the closing quote must be a comment
+	p.skipSpaces()
+	if !p.eof() && p.peek() != '\n' && p.peek() != '#' {
+		return "", false, p.errorAt(p.pos, "unexpected characters after closing quote")
+	}
+	p.skipLine()
+	return value, literal, nil
+}
+
+// readUnquoted reads up to the end of the line or an inline comment
+// The whitespace after "=" counts too, so "KEY=   # comment" is empty.
+func (p *envParser) readUnquoted() string {
+	start := p.pos
+	for !p.eof() && p.peek() != '\n' {
+		if p.peek() == '#' && (p.src[p.pos-1] == ' ' || p.src[p.pos-1] == '\t') {
+			value := p.src[start:p.pos]
+			p.skipLine()
+			return strings.TrimSpace(value)
+		}
+		p.next()
+	}
+	value := p.src[start:p.pos]
+	p.skipLine()
+	return strings.TrimSpace(value)
+}
+
+// readDoubleQuoted reads a double-quoted value with escape sequences
+// A value without an unescaped "$" is returned decoded and literal, otherwise
+// \$ is kept as "$$" for interpolation.
+func (p *envParser) readDoubleQuoted() (string, bool, error) {
+	var b strings.Builder
+	dynamic := false
+	for !p.eof() {
+		ch := p.next()
+		switch ch {
+		case '"':
+			value := b.String()
+			if !dynamic {
+				return strings.ReplaceAll(value, "$$", "$"), true, nil
+			}
+			return value, false, nil
+		case '$':
+			dynamic = true
+			b.WriteByte(ch)
+		case '\\':
+			if p.eof() {
+				return "", false, fmt.Errorf("unterminated double-quoted value")
+			}
+			switch esc := p.next(); esc {
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			case '"', '\\':
+				b.WriteByte(esc)
+			case '$':
+				b.WriteString("$$")
+			default:
+				b.WriteByte('\\')
+				b.WriteByte(esc)
+			}
+		default:
+			b.WriteByte(ch)
+		}
+	}
+	return "", false, fmt.Errorf("unterminated double-quoted value")
+}
+
+// readLiteral reads a single-quoted or backtick value without escapes
+func (p *envParser) readLiteral(quote byte) (string, error) {
+	end := strings.IndexByte(p.src[p.pos:], quote)
+	if end < 0 {
+		return "", fmt.Errorf("unterminated %c-quoted value", quote)
+	}
+	value := p.src[p.pos : p.pos+end]
+	for i := 0; i <= end; i++ {
+		p.next()
+	}
+	return value, nil
+}
+
+// isEnvKey reports whether key is a valid .env variable name
+func isEnvKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	for _, r := range key {
+		if !(r == '_' || r == '.' || r == '-' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,170 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestEnvParserConformance(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		key   string
+		value string
+	}{
+		{"basic", "KEY=value", "KEY", "value"},
+		{"surrounding spaces", "  KEY = value  ", "KEY", "value"},
+		{"empty value", "KEY=", "KEY", ""},
+		{"empty quoted value", `KEY=""`, "KEY", ""},
+		{"export prefix", "export KEY=value", "KEY", "value"},
+		{"export with spaces", "export   KEY=value", "KEY", "value"},
+		{"equals in value", "KEY=a=b=c", "KEY", "a=b=c"},
+		{"inline comment", "KEY=value # comment", "KEY", "value"},
+		{"hash without space", "KEY=value#not-comment", "KEY", "value#not-comment"},
+		{"hash at start of value", "KEY=#not-comment", "KEY", "#not-comment"},
+		{"comment after empty value", "KEY=   # comment", "KEY", ""},
+		{"comment after tab", "KEY=\t# comment", "KEY", ""},
+		{"double quoted", `KEY="quoted value"`, "KEY", "quoted value"},
+		{"double quoted hash", `KEY="value # not comment"`, "KEY", "value # not comment"},
+		{"double quoted comment after", `KEY="value" # comment`, "KEY", "value"},
+		{"newline escape", `KEY="line1\nline2"`, "KEY", "line1\nline2"},
+		{"tab escape", `KEY="a\tb"`, "KEY", "a\tb"},
+		{"quote escape", `KEY="say \"hi\""`, "KEY", `say "hi"`},
+		{"backslash escape", `KEY="C:\\path"`, "KEY", `C:\path`},
+		{"unknown escape kept", `KEY="a\qb"`, "KEY", `a\qb`},
+		{"dollar escape", `KEY="\$HOME"`, "KEY", "$HOME"},
+		{"dollar escape with reference", `KEY="\$5 ${X}"`, "KEY", "$$5 ${X}"},
+		{"multi-line double quoted", "KEY=\"line1\nline2\"", "KEY", "line1\nline2"},
+		{"single quoted literal", `KEY='a\nb'`, "KEY", `a\nb`},
+		{"single quoted hash", `KEY='a # b'`, "KEY", "a # b"},
+		{"single quoted dollar", `KEY='${HOME}'`, "KEY", "${HOME}"},
+		{"multi-line single quoted", "KEY='line1\nline2'", "KEY", "line1\nline2"},
+		{"backtick", "KEY=`it's \"mixed\"`", "KEY", `it's "mixed"`},
+		{"windows line endings", "KEY=value\r\n", "KEY", "value"},
+		{"dotted key", "database.host=localhost", "database.host", "localhost"},
+	}
+
+	for _, test := range tests {
+		entries, err := parseEnv([]byte(test.input), false)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if len(entries) != 1 {
+			t.Errorf("%s: expected 1 entry, got %d", test.name, len(entries))
+			continue
+		}
+		if entries[0].key != test.key || entries[0].value != test.value {
+			t.Errorf("%s: expected %s=%q, got %s=%q", test.name, test.key, test.value, entries[0].key, entries[0].value)
+		}
+	}
+}
+
+func TestEnvParserDocument(t *testing.T) {
+	input := `# comment
+export FIRST=1
+
+  # indented comment
+not an assignment
+MULTI="first
+second"
+LAST=3 # trailing
+`
+	entries, err := parseEnv([]byte(input), false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []envEntry{
+		{key: "FIRST", value: "1", line: 2},
+		{key: "MULTI", value: "first\nsecond", line: 6, literal: true},
+		{key: "LAST", value: "3", line: 8},
+	}
+	if len(entries) != len(expected) {
+		t.Fatalf("Expected %d entries, got %+v", len(expected), entries)
+	}
+	for i, entry := range entries {
+		if entry != expected[i] {
+			t.Errorf("Expected entry %+v, got %+v", expected[i], entry)
+		}
+	}
+
+	// Malformed values are skipped without strict mode and rejected with it
+	for _, input := range []string{"KEY=\"unterminated\nNEXT=2", "KEY='unterminated\nNEXT=2", "KEY=\"value\" trailing\nNEXT=2"} {
+		entries, err := parseEnv([]byte(input), false)
+		if err != nil {
+			t.Errorf("Unexpected error for %q: %v", input, err)
+		} else if len(entries) != 1 || entries[0] != (envEntry{key: "NEXT", value: "2", line: 2}) {
+			t.Errorf("Expected only NEXT=2 for %q, got %+v", input, entries)
+		}
+		if _, err := parseEnv([]byte(input), true); err == nil {
+			t.Errorf("Expected error for %q in strict mode", input)
+		}
+	}
+}
+
+func TestEnvParserLiteralsSkipInterpolation(t *testing.T) {
+	envContent := `PARSER_HOST=example.com
+PARSER_URL="https://${PARSER_HOST}"
+PARSER_LITERAL='${PARSER_HOST}'
+PARSER_ESCAPED="\${PARSER_HOST}"
+`
+	err := createTestFile("parser_literal.env", envContent)
+	if err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	defer cleanupTestFile("parser_literal.env")
+
+	config := NewWithOptions("parser_literal.env", WithIsolated())
+	if config.Str("PARSER_URL") != "https://example.com" {
+		t.Errorf("Expected PARSER_URL=https://example.com, got %s", config.Str("PARSER_URL"))
+	}
+	if config.Str("PARSER_LITERAL") != "${PARSER_HOST}" {
+		t.Errorf("Expected PARSER_LITERAL=${PARSER_HOST}, got %s", config.Str("PARSER_LITERAL"))
+	}
+	if config.Str("PARSER_ESCAPED") != "${PARSER_HOST}" {
+		t.Errorf("Expected PARSER_ESCAPED=${PARSER_HOST}, got %s", config.Str("PARSER_ESCAPED"))
+	}
+
+	// The global loader uses the same parser
+	defer os.Unsetenv("PARSER_HOST")
+	defer os.Unsetenv("PARSER_URL")
+	defer os.Unsetenv("PARSER_LITERAL")
+	defer os.Unsetenv("PARSER_ESCAPED")
+	if err := LoadEnvFile("parser_literal.env"); err != nil {
+		t.Fatalf("Failed to load env file: %v", err)
+	}
+	if Str("PARSER_LITERAL") != "${PARSER_HOST}" {
+		t.Errorf("Expected PARSER_LITERAL=${PARSER_HOST}, got %s", Str("PARSER_LITERAL"))
+	}
+}
+
+func TestEnvLiteralsFromSources(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".env")
+	content := "A='it costs $5'\nB=\"\\$HOME\"\nC=\"\\$5 for ${D}\"\nD=x\nE='${D}'\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	// Sources that do not interpolate return the values as written
+	expected := map[string]interface{}{"A": "it costs $5", "B": "$HOME", "C": "$$5 for ${D}", "D": "x", "E": "${D}"}
+	for _, source := range []Source{FileSource(path), &Dotenv{Dir: dir}} {
+		values, err := source.Load()
+		if err != nil {
+			t.Fatalf("Failed to load %s: %v", source.Name(), err)
+		}
+		if !reflect.DeepEqual(values, expected) {
+			t.Errorf("Expected %s to load %v, got %v", source.Name(), expected, values)
+		}
+	}
+
+	config := NewWithOptions(path, WithIsolated())
+	for key, want := range map[string]string{"A": "it costs $5", "B": "$HOME", "C": "$5 for x", "E": "${D}"} {
+		if value := config.Str(key); value != want {
+			t.Errorf("Expected %s=%q, got %q", key, want, value)
+		}
+	}
+}
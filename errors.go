@@ -0,0 +1,203 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// ParseError describes a syntax error in a config file
+type ParseError struct {
+	File   string // Path of the config file
+	Line   int    // 1-based line number
+	Column int    // 1-based column, 0 when unknown
+	Text   string // Offending line
+	Reason string
+	Err    error // Underlying decoder error, if any
+}
+
+func (e *ParseError) Error() string {
+	location := fmt.Sprintf("line %d", e.Line)
+	if e.File != "" {
+		location = fmt.Sprintf("%s:%d", e.File, e.Line)
+		if e.Column > 0 {
+			location += fmt.Sprintf(":%d", e.Column)
+		}
+	} else if e.Column > 0 {
+		location += fmt.Sprintf(", column %d", e.Column)
+	}
+
+	msg := fmt.Sprintf("failed to parse config %s: %s", location, e.Reason)
+	if text := strings.TrimSpace(e.Text); text != "" {
+		msg += fmt.Sprintf(" in %q", text)
+	}
+	return msg
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// withFile records the file name on a ParseError returned by a parser or on schema violations
+func withFile(err error, filePath string) error {
+	var parseErr *ParseError
+	if errors.As(err, &parseErr) && parseErr.File == "" {
+		parseErr.File = filePath
+	}
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		for i := range validationErr.Violations {
+			if validationErr.Violations[i].File == "" {
+				validationErr.Violations[i].File = filePath
+			}
+		}
+	}
+	return err
+}
+
+// offsetError returns a ParseError for a byte offset into data
+func offsetError(data []byte, offset int64, reason string, err error) *ParseError {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+
+	before := data[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	lineStart := bytes.LastIndexByte(before, '\n') + 1
+	lineEnd := bytes.IndexByte(data[lineStart:], '\n')
+	if lineEnd < 0 {
+		lineEnd = len(data) - lineStart
+	}
+
+	column := int(offset) - lineStart
+	if column < 1 {
+		column = 1
+	}
+	return &ParseError{
+		Line:   line,
+		Column: column,
+		Text:   string(data[lineStart : lineStart+lineEnd]),
+		Reason: reason,
+		Err:    err,
+	}
+}
+
+// jsonParseError converts an encoding/json error into a ParseError
+func jsonParseError(data []byte, err error) error {
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return offsetError(data, syntaxErr.Offset, syntaxErr.Error(), err)
+	}
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return offsetError(data, typeErr.Offset, typeErr.Error(), err)
+	}
+	if errors.Is(err, io.ErrUnexpectedEOF) || err == io.EOF {
+		return offsetError(data, int64(len(data)), "unexpected end of JSON input", err)
+	}
+	return fmt.Errorf("failed to parse JSON config: %w", err)
+}
+
+// yamlLinePattern extracts the line number from yaml.v3 error messages
+var yamlLinePattern = regexp.MustCompile(`^(?:yaml: )?line (\d+): (.*)$`)
+
+// yamlParseError converts a yaml.v3 error into a ParseError
+func yamlParseError(data []byte, err error) error {
+	message := err.Error()
+	var typeErr *yaml.TypeError
+	if errors.As(err, &typeErr) && len(typeErr.Errors) > 0 {
+		message = typeErr.Errors[0]
+	}
+
+	match := yamlLinePattern.FindStringSubmatch(message)
+	if match == nil {
+		return fmt.Errorf("failed to parse YAML config: %w", err)
+	}
+
+	line, _ := strconv.Atoi(match[1])
+	text := ""
+	if lines := strings.Split(string(data), "\n"); line >= 1 && line <= len(lines) {
+		text = lines[line-1]
+	}
+	return &ParseError{Line: line, Text: text, Reason: match[2], Err: err}
+}
+
+// tomlParseError converts a go-toml error into a ParseError
+func tomlParseError(data []byte, err error) error {
+	var decodeErr *toml.DecodeError
+	if !errors.As(err, &decodeErr) {
+		return fmt.Errorf("failed to parse TOML config: %w", err)
+	}
+
+	line, column := decodeErr.Position()
+	text := ""
+	if lines := strings.Split(string(data), "\n"); line >= 1 && line <= len(lines) {
+		text = lines[line-1]
+	}
+	return &ParseError{Line: line, Column: column, Text: text, Reason: decodeErr.Error(), Err: err}
+}
+
+// checkJSONDuplicates returns a ParseError for the first object key defined twice
+func checkJSONDuplicates(data []byte) error {
+	type frame struct {
+		object    bool
+		expectKey bool
+		keys      map[string]bool
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	var stack []*frame
+	for {
+		offset := dec.InputOffset()
+		token, err := dec.Token()
+		if err != nil {
+			// Syntax errors are reported by the regular decoder
+			return nil
+		}
+
+		var top *frame
+		if len(stack) > 0 {
+			top = stack[len(stack)-1]
+		}
+
+		if key, ok := token.(string); ok && top != nil && top.object && top.expectKey {
+			if top.keys[key] {
+				// Point at the key rather than the preceding separator
+				for offset < int64(len(data)) && strings.IndexByte(" \t\r\n,", data[offset]) >= 0 {
+					offset++
+				}
+				return offsetError(data, offset+1, fmt.Sprintf("duplicate key %q", key), nil)
+			}
+			top.keys[key] = true
+			top.expectKey = false
+			continue
+		}
+
+		switch token {
+		case json.Delim('{'):
+			stack = append(stack, &frame{object: true, expectKey: true, keys: make(map[string]bool)})
+		case json.Delim('['):
+			stack = append(stack, &frame{})
+		case json.Delim('}'), json.Delim(']'):
+			stack = stack[:len(stack)-1]
+			if len(stack) > 0 && stack[len(stack)-1].object {
+				stack[len(stack)-1].expectKey = true
+			}
+		default:
+			if top != nil && top.object {
+				top.expectKey = true
+			}
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		line    int
+		column  int
+		reason  string
+	}{
+		{"json syntax", "parse_error.json", "{\n  \"a\": 1,\n  \"b\": ,\n}", 3, 8, "invalid character"},
+		{"json truncated", "parse_error_eof.json", "{\n  \"a\": 1", 2, 0, "unexpected end"},
+		{"yaml syntax", "parse_error.yaml", "a: 1\nb: [1, 2\nc: 3\n", 0, 0, "did not find expected"},
+		{"yaml duplicate", "parse_error_dup.yaml", "a: 1\nb: 2\na: 3\n", 3, 0, "already defined"},
+	}
+
+	for _, test := range tests {
+		if err := createTestFile(test.file, test.content); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+		defer cleanupTestFile(test.file)
+
+		err := LoadConfigFile(test.file)
+		var parseErr *ParseError
+		if !errors.As(err, &parseErr) {
+			t.Errorf("%s: expected *ParseError, got %v", test.name, err)
+			continue
+		}
+		if parseErr.File != test.file {
+			t.Errorf("%s: expected file %s, got %s", test.name, test.file, parseErr.File)
+		}
+		if test.line > 0 && parseErr.Line != test.line {
+			t.Errorf("%s: expected line %d, got %d (%v)", test.name, test.line, parseErr.Line, err)
+		}
+		if test.column > 0 && parseErr.Column != test.column {
+			t.Errorf("%s: expected column %d, got %d (%v)", test.name, test.column, parseErr.Column, err)
+		}
+		if !strings.Contains(parseErr.Reason, test.reason) {
+			t.Errorf("%s: expected reason containing %q, got %q", test.name, test.reason, parseErr.Reason)
+		}
+	}
+}
+
+func TestStrictMode(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		line    int
+		reason  string
+	}{
+		{"malformed line", "strict_malformed.env", "A=1\nnot an assignment\n", 2, "missing '='"},
+		{"invalid key", "strict_key.env", "A=1\nBAD KEY=2\n", 2, "invalid key"},
+		{"env duplicate", "strict_dup.env", "A=1\nB=2\nA=3\n", 3, "duplicate key A"},
+		{"unterminated quote", "strict_open.env", "A=1\nB=\"open\n", 2, "unterminated"},
+		{"text after quote", "strict_trailing.env", "A=1\nB=\"x\" y\n", 2, "unexpected characters"},
+		{"json duplicate", "strict_dup.json", "{\n  \"a\": {\"x\": 1},\n  \"a\": 2\n}", 3, `duplicate key "a"`},
+	}
+
+	for _, test := range tests {
+		if err := createTestFile(test.file, test.content); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+		defer cleanupTestFile(test.file)
+
+		// Lenient mode skips the problem
+		if err := NewWithOptions(test.file, WithIsolated()).Load(); err != nil {
+			t.Errorf("%s: expected lenient load to succeed, got %v", test.name, err)
+		}
+
+		config := NewWithOptions(test.file, WithIsolated(), WithStrict())
+		err := config.Load()
+		var parseErr *ParseError
+		if !errors.As(err, &parseErr) {
+			t.Errorf("%s: expected *ParseError, got %v", test.name, err)
+			continue
+		}
+		if parseErr.Line != test.line || !strings.Contains(parseErr.Reason, test.reason) {
+			t.Errorf("%s: expected line %d with %q, got %v", test.name, test.line, test.reason, err)
+		}
+	}
+}
+
+func TestCheckJSONDuplicatesNested(t *testing.T) {
+	valid := `{"a": {"x": 1, "y": [1, {"x": 2}]}, "b": {"x": 3}}`
+	if err := checkJSONDuplicates([]byte(valid)); err != nil {
+		t.Errorf("Expected no duplicates, got %v", err)
+	}
+	if err := checkJSONDuplicates([]byte(`{"a": [{"x": 1, "x": 2}]}`)); err == nil {
+		t.Errorf("Expected duplicate key error inside array")
+	}
+}
@@ -1,12 +1,17 @@
 package config
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/pelletier/go-toml/v2"
 	"gopkg.in/yaml.v3"
 )
 
@@ -17,16 +22,29 @@ const (
 	FormatEnv ConfigFormat = iota
 	FormatJSON
 	FormatYAML
+	FormatTOML
+	FormatINI
+	FormatProperties
 )
 
 // detectFormat detects the configuration file format based on file extension
+// Formats added with RegisterFormat take precedence over the built-in ones
 func detectFormat(filePath string) ConfigFormat {
 	ext := strings.ToLower(filepath.Ext(filePath))
+	if format, ok := registeredFormat(ext); ok {
+		return format
+	}
 	switch ext {
 	case ".json":
 		return FormatJSON
 	case ".yml", ".yaml":
 		return FormatYAML
+	case ".toml":
+		return FormatTOML
+	case ".ini":
+		return FormatINI
+	case ".properties":
+		return FormatProperties
 	default:
 		return FormatEnv
 	}
@@ -34,8 +52,13 @@ func detectFormat(filePath string) ConfigFormat {
 
 // loadConfigFile loads configuration from various file formats
 func loadConfigFile(filePath string) (map[string]interface{}, error) {
-	format := detectFormat(filePath)
+	return loadConfigFileAs(filePath, detectFormat(filePath), loadOptions{})
+}
 
+// loadConfigFileAs loads configuration from a file in the given format
+// In strict mode malformed .env lines and duplicate keys are errors instead of being skipped.
+// JSON, YAML and TOML documents are checked against the schema before flattening.
+func loadConfigFileAs(filePath string, format ConfigFormat, opts loadOptions) (map[string]interface{}, error) {
 	data, err := os.ReadFile(filePath)
 	if err != nil {
 		// If file doesn't exist, return empty config
@@ -45,73 +68,123 @@ func loadConfigFile(filePath string) (map[string]interface{}, error) {
 		return nil, fmt.Errorf("failed to read config file %s: %w", filePath, err)
 	}
 
+	var config map[string]interface{}
 	switch format {
 	case FormatJSON:
-		return loadJSONConfig(data)
+		config, err = loadJSONConfig(data, opts)
 	case FormatYAML:
-		return loadYAMLConfig(data)
+		config, err = loadYAMLConfig(data, opts.schema)
+	case FormatTOML:
+		config, err = loadTOMLConfig(data, opts.schema)
+	case FormatINI:
+		config, err = loadINIConfig(data, opts.strict)
+	case FormatProperties:
+		config, err = loadPropertiesConfig(data, opts.strict)
 	case FormatEnv:
-		return loadEnvConfig(data)
+		config, err = loadEnvConfig(data, opts.strict)
 	default:
-		return nil, fmt.Errorf("unsupported config format for file: %s", filePath)
+		decoder, ok := registeredDecoder(format)
+		if !ok {
+			return nil, fmt.Errorf("unsupported config format for file: %s", filePath)
+		}
+		config, err = decodeRegistered(decoder, data, filePath)
 	}
+	if err != nil {
+		return nil, withFile(err, filePath)
+	}
+	return config, nil
 }
 
 // loadJSONConfig loads configuration from JSON data
-func loadJSONConfig(data []byte) (map[string]interface{}, error) {
+// Numbers are kept as json.Number so they read back exactly as written.
+func loadJSONConfig(data []byte, opts loadOptions) (map[string]interface{}, error) {
 	var config map[string]interface{}
-	if err := json.Unmarshal(data, &config); err != nil {
-		return nil, fmt.Errorf("failed to parse JSON config: %w", err)
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&config); err != nil {
+		return nil, jsonParseError(data, err)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, offsetError(data, dec.InputOffset(), "invalid character after top-level value", err)
+	}
+	if opts.strict {
+		if err := checkJSONDuplicates(data); err != nil {
+			return nil, err
+		}
+	}
+	if err := checkSchema(opts.schema, config, data, jsonLines); err != nil {
+		return nil, err
 	}
 	return flattenConfig(config, ""), nil
 }
 
 // loadYAMLConfig loads configuration from YAML data
-func loadYAMLConfig(data []byte) (map[string]interface{}, error) {
+// yaml.v3 always rejects duplicate keys
+func loadYAMLConfig(data []byte, schema *Schema) (map[string]interface{}, error) {
 	var config map[string]interface{}
 	if err := yaml.Unmarshal(data, &config); err != nil {
-		return nil, fmt.Errorf("failed to parse YAML config: %w", err)
+		return nil, yamlParseError(data, err)
+	}
+	if err := checkSchema(schema, config, data, yamlLines); err != nil {
+		return nil, err
 	}
 	return flattenConfig(config, ""), nil
 }
 
-// loadEnvConfig loads configuration from ENV data
-func loadEnvConfig(data []byte) (map[string]interface{}, error) {
-	config := make(map[string]interface{})
-	lines := strings.Split(string(data), "\n")
-
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-
-		// Skip empty lines and comments
-		if line == "" || strings.HasPrefix(line, "#") {
-			continue
-		}
+// loadTOMLConfig loads configuration from TOML data
+// go-toml always rejects duplicate keys
+func loadTOMLConfig(data []byte, schema *Schema) (map[string]interface{}, error) {
+	var config map[string]interface{}
+	if err := toml.Unmarshal(data, &config); err != nil {
+		return nil, tomlParseError(data, err)
+	}
+	// go-toml does not report positions of decoded values
+	if err := checkSchema(schema, config, data, nil); err != nil {
+		return nil, err
+	}
+	return flattenConfig(config, ""), nil
+}
 
-		// Parse key=value
-		parts := strings.SplitN(line, "=", 2)
-		if len(parts) != 2 {
-			continue
-		}
+// checkSchema validates a decoded document, locating violations with lines when it is not nil
+func checkSchema(schema *Schema, config map[string]interface{}, data []byte, lines func([]byte) map[string]int) error {
+	if schema == nil {
+		return nil
+	}
+	var doc interface{} = config
+	if config == nil {
+		doc = map[string]interface{}{} // An empty YAML file has no document
+	}
+	errs := schema.validate(doc)
+	if len(errs) == 0 {
+		return nil
+	}
+	var located map[string]int
+	if lines != nil {
+		located = lines(data)
+	}
+	return schemaViolations(errs, located)
+}
 
-		key := strings.TrimSpace(parts[0])
-		value := strings.TrimSpace(parts[1])
+// loadEnvConfig loads configuration from ENV data
+func loadEnvConfig(data []byte, strict bool) (map[string]interface{}, error) {
+	entries, err := parseEnv(data, strict)
+	if err != nil {
+		return nil, err
+	}
 
-		// Remove quotes if present
-		if len(value) >= 2 {
-			if (value[0] == '"' && value[len(value)-1] == '"') ||
-				(value[0] == '\'' && value[len(value)-1] == '\'') {
-				value = value[1 : len(value)-1]
-			}
+	config := make(map[string]interface{}, len(entries))
+	for _, entry := range entries {
+		if entry.literal {
+			config[entry.key] = literal(entry.value)
+		} else {
+			config[entry.key] = entry.value
 		}
-
-		config[key] = value
 	}
-
 	return config, nil
 }
 
 // flattenConfig flattens nested configuration into dot notation
+// Leaf values keep the type they were decoded with, see formatValue.
 func flattenConfig(config map[string]interface{}, prefix string) map[string]interface{} {
 	result := make(map[string]interface{})
 
@@ -129,36 +202,85 @@ func flattenConfig(config map[string]interface{}, prefix string) map[string]inte
 				result[nestedKey] = nestedValue
 			}
 		case []interface{}:
-			// Convert arrays to comma-separated strings
-			var strValues []string
-			for _, item := range v {
-				strValues = append(strValues, fmt.Sprintf("%v", item))
+			// Arrays holding objects or arrays are addressed by index, e.g. servers.0.host
+			if hasCompositeItem(v) {
+				for i, item := range v {
+					nested := flattenConfig(map[string]interface{}{strconv.Itoa(i): item}, fullKey)
+					for nestedKey, nestedValue := range nested {
+						result[nestedKey] = nestedValue
+					}
+				}
+				continue
 			}
-			result[fullKey] = strings.Join(strValues, ",")
+
+			// Arrays of scalars stay whole and read as comma-separated lists
+			result[fullKey] = v
 		default:
-			result[fullKey] = fmt.Sprintf("%v", value)
+			result[fullKey] = value
 		}
 	}
 
 	return result
 }
 
-// setEnvironmentVariables sets environment variables from config map
-func setEnvironmentVariables(config map[string]interface{}) {
-	for key, value := range config {
-		// Convert key to uppercase for environment variables
-		envKey := strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+// hasCompositeItem reports whether arr holds an object or an array
+func hasCompositeItem(arr []interface{}) bool {
+	for _, item := range arr {
+		switch item.(type) {
+		case map[string]interface{}, []interface{}:
+			return true
+		}
+	}
+	return false
+}
 
-		// Always set the environment variable (allow override for reload)
-		os.Setenv(envKey, fmt.Sprintf("%v", value))
+// formatValue converts a decoded leaf value to its canonical string form
+// Numbers are written in decimal without an exponent, null is empty and arrays
+// of scalars are joined with commas, quoting elements where needed.
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case json.Number:
+		return v.String()
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case []interface{}:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = formatValue(item)
+		}
+		return joinList(items)
+	}
+	return fmt.Sprint(value)
+}
+
+// toEnvKey converts a flattened config key to its environment variable name
+func toEnvKey(key string) string {
+	return strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// envValues converts a flattened config map to environment variable names and values
+func envValues(config map[string]interface{}) map[string]string {
+	values := make(map[string]string, len(config))
+	for key, value := range config {
+		values[toEnvKey(key)] = formatValue(value)
 	}
+	return values
 }
 
-// clearEnvironmentVariables clears environment variables that were set from config
-func clearEnvironmentVariables(config map[string]interface{}) {
-	for key := range config {
-		// Convert key to uppercase for environment variables
-		envKey := strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
-		os.Unsetenv(envKey)
+// setEnvironmentVariables sets environment variables from config map
+func setEnvironmentVariables(config map[string]interface{}) {
+	for envKey, value := range envValues(config) {
+		// Always set the environment variable (allow override for reload)
+		os.Setenv(envKey, value)
 	}
 }
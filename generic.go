@@ -0,0 +1,194 @@
+package config
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ValueDecoder is implemented by types that decode themselves from a configuration value
+// It takes precedence over encoding.TextUnmarshaler.
+type ValueDecoder interface {
+	DecodeValue(value string) error
+}
+
+var (
+	valueDecoderType    = reflect.TypeOf((*ValueDecoder)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// errUnsupportedType is returned when a value cannot be decoded into a Go type at all
+var errUnsupportedType = errors.New("unsupported type")
+
+// valueFormat holds the Config settings used to convert values
+type valueFormat struct {
+	timeLayouts []string // Layouts tried after RFC3339
+	separator   string   // List separator, defaultSeparator when empty
+}
+
+// valueFormat returns the conversion settings of c, the defaults for a nil Config
+func (c *Config) valueFormat() valueFormat {
+	if c == nil {
+		return valueFormat{}
+	}
+	return valueFormat{timeLayouts: c.timeLayouts, separator: c.separator()}
+}
+
+// Get retrieves the value of key converted to T, or the default if it is missing, empty or invalid
+//
+// T may be any type implementing ValueDecoder or encoding.TextUnmarshaler
+// through a pointer receiver, time.Duration, time.Time, a string, bool,
+// integer or float kind, a pointer to one of these, or a slice or
+// string-keyed map of them, read like StrSlice and StringMap. A nil Config
+// reads the process environment like the package-level getters.
+//
+//	level := config.Get(cfg, "LOG_LEVEL", slog.LevelInfo)
+//	ports := config.Get[[]uint16](cfg, "PORTS")
+func Get[T any](c *Config, key string, defaultValue ...T) T {
+	value, _ := lookupValue(c, key)
+	return typedValue(value, valueParser[T](c.valueFormat()), defaultValue)
+}
+
+// GetE retrieves the value of key converted to T, returning ErrNotFound or a *ConversionError
+// See Get for the supported types.
+func GetE[T any](c *Config, key string) (T, error) {
+	value, ok := lookupValue(c, key)
+	return typedValueE(key, value, ok, valueParser[T](c.valueFormat()))
+}
+
+// lookupValue looks key up in c, or in the process environment when c is nil
+func lookupValue(c *Config, key string) (string, bool) {
+	if c == nil {
+		return os.LookupEnv(key)
+	}
+	return c.lookup(key)
+}
+
+// valueParser returns a parse function converting values to T
+func valueParser[T any](format valueFormat) func(string) (T, error) {
+	return func(value string) (T, error) {
+		var result T
+		err := decodeValue(reflect.ValueOf(&result).Elem(), value, format)
+		return result, err
+	}
+}
+
+// decodeValue converts value to the type of fv and stores it
+func decodeValue(fv reflect.Value, value string, format valueFormat) error {
+	ft := fv.Type()
+
+	switch ft {
+	case durationType:
+		d, err := parseDuration(value)
+		if err != nil {
+			return err
+		}
+		fv.SetInt(int64(d))
+		return nil
+	case timeType:
+		t, err := timeParser(format.timeLayouts)(value)
+		if err != nil {
+			return err
+		}
+		fv.Set(reflect.ValueOf(t))
+		return nil
+	}
+
+	if ft.Kind() != reflect.Pointer && fv.CanAddr() {
+		switch target := fv.Addr().Interface().(type) {
+		case ValueDecoder:
+			return target.DecodeValue(value)
+		case encoding.TextUnmarshaler:
+			return target.UnmarshalText([]byte(value))
+		}
+	}
+
+	switch ft.Kind() {
+	case reflect.Pointer:
+		elem := reflect.New(ft.Elem())
+		if err := decodeValue(elem.Elem(), value, format); err != nil {
+			return err
+		}
+		fv.Set(elem)
+	case reflect.String:
+		fv.SetString(value)
+	case reflect.Bool:
+		b, err := parseBool(value)
+		if err != nil {
+			return err
+		}
+		fv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(strings.TrimSpace(value), 10, ft.Bits())
+		if err != nil {
+			return err.(*strconv.NumError).Err
+		}
+		fv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(strings.TrimSpace(value), 10, ft.Bits())
+		if err != nil {
+			return err.(*strconv.NumError).Err
+		}
+		fv.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(strings.TrimSpace(value), ft.Bits())
+		if err != nil {
+			return err.(*strconv.NumError).Err
+		}
+		fv.SetFloat(f)
+	case reflect.Slice:
+		if ft.Elem().Kind() == reflect.Uint8 {
+			fv.SetBytes([]byte(value))
+			return nil
+		}
+		parts, err := splitList(value, format.listSeparator())
+		if err != nil {
+			return err
+		}
+		slice := reflect.MakeSlice(ft, len(parts), len(parts))
+		for i, part := range parts {
+			if err := decodeValue(slice.Index(i), part, format); err != nil {
+				return fmt.Errorf("element %d: %w", i, err)
+			}
+		}
+		fv.Set(slice)
+	case reflect.Map:
+		if ft.Key().Kind() != reflect.String {
+			return errUnsupportedType
+		}
+		pairs, err := mapParser(format.listSeparator())(value)
+		if err != nil {
+			return err
+		}
+		m := reflect.MakeMapWithSize(ft, len(pairs))
+		for k, v := range pairs {
+			elem := reflect.New(ft.Elem()).Elem()
+			if err := decodeValue(elem, v, format); err != nil {
+				return fmt.Errorf("value of %q: %w", k, err)
+			}
+			m.SetMapIndex(reflect.ValueOf(k).Convert(ft.Key()), elem)
+		}
+		fv.Set(m)
+	default:
+		return errUnsupportedType
+	}
+	return nil
+}
+
+// listSeparator returns the separator lists and maps are split on
+func (f valueFormat) listSeparator() string {
+	if f.separator != "" {
+		return f.separator
+	}
+	return defaultSeparator
+}
+
+// implementsDecoder reports whether a pointer to t decodes itself from a value
+func implementsDecoder(t reflect.Type) bool {
+	pt := reflect.PointerTo(t)
+	return pt.Implements(valueDecoderType) || pt.Implements(textUnmarshalerType)
+}
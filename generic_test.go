@@ -0,0 +1,194 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/netip"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testLevel decodes itself with ValueDecoder
+type testLevel int
+
+func (l *testLevel) DecodeValue(value string) error {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "low":
+		*l = 1
+	case "high":
+		*l = 2
+	default:
+		return fmt.Errorf("unknown level %q", value)
+	}
+	return nil
+}
+
+// testBoth implements both interfaces, ValueDecoder wins
+type testBoth string
+
+func (b *testBoth) DecodeValue(value string) error {
+	*b = testBoth("value:" + value)
+	return nil
+}
+
+func (b *testBoth) UnmarshalText(text []byte) error {
+	*b = testBoth("text:" + string(text))
+	return nil
+}
+
+func TestGet(t *testing.T) {
+	config := NewWithOptions("",
+		WithIsolated(),
+		WithSeparator(";"),
+		WithTimeLayouts("2006-01-02"),
+		WithSources(DefaultsSource(map[string]interface{}{
+			"level":   "high",
+			"slog":    "warn",
+			"addr":    "10.0.0.1",
+			"subnet":  "10.0.0.0/8",
+			"ports":   "80; 443",
+			"weights": "a=1;b=2",
+			"count":   " 7 ",
+			"small":   "300",
+			"ratio":   "0.25",
+			"enabled": "yes",
+			"timeout": "1m",
+			"started": "2024-05-01",
+			"both":    "x",
+			"blank":   "",
+		})),
+	)
+
+	if value := Get[testLevel](config, "LEVEL"); value != 2 {
+		t.Errorf("Expected LEVEL=2, got %d", value)
+	}
+	if value := Get(config, "SLOG", slog.LevelInfo); value != slog.LevelWarn {
+		t.Errorf("Expected SLOG=WARN, got %v", value)
+	}
+	if value := Get[netip.Addr](config, "ADDR"); value != netip.MustParseAddr("10.0.0.1") {
+		t.Errorf("Expected ADDR=10.0.0.1, got %v", value)
+	}
+	if value := Get[netip.Prefix](config, "SUBNET"); value.Bits() != 8 {
+		t.Errorf("Expected a /8 SUBNET, got %v", value)
+	}
+	if value := Get[[]uint16](config, "PORTS"); !reflect.DeepEqual(value, []uint16{80, 443}) {
+		t.Errorf("Expected PORTS=[80 443], got %v", value)
+	}
+	if value := Get[map[string]int](config, "WEIGHTS"); !reflect.DeepEqual(value, map[string]int{"a": 1, "b": 2}) {
+		t.Errorf("Expected WEIGHTS map, got %v", value)
+	}
+	if value := Get[*int](config, "COUNT"); value == nil || *value != 7 {
+		t.Errorf("Expected COUNT=7, got %v", value)
+	}
+	if value := Get[float32](config, "RATIO"); value != 0.25 {
+		t.Errorf("Expected RATIO=0.25, got %v", value)
+	}
+	if value := Get[bool](config, "ENABLED"); !value {
+		t.Error("Expected ENABLED=true")
+	}
+	if value := Get[time.Duration](config, "TIMEOUT"); value != time.Minute {
+		t.Errorf("Expected TIMEOUT=1m, got %v", value)
+	}
+	if value := Get[time.Time](config, "STARTED"); !value.Equal(time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected STARTED to use the configured layout, got %v", value)
+	}
+	if value := Get[testBoth](config, "BOTH"); value != "value:x" {
+		t.Errorf("Expected ValueDecoder to take precedence, got %s", value)
+	}
+
+	// Missing, empty and invalid values fall back to the default
+	if value := Get(config, "MISSING", 5); value != 5 {
+		t.Errorf("Expected the default for a missing key, got %d", value)
+	}
+	if value := Get(config, "BLANK", testLevel(1)); value != 1 {
+		t.Errorf("Expected the default for an empty value, got %d", value)
+	}
+	if value := Get[int8](config, "SMALL", 3); value != 3 {
+		t.Errorf("Expected the default for an out of range value, got %d", value)
+	}
+}
+
+func TestGetE(t *testing.T) {
+	config := NewWithOptions("",
+		WithIsolated(),
+		WithSources(DefaultsSource(map[string]interface{}{
+			"level": "medium",
+			"small": "300",
+			"ports": "80,http",
+			"addr":  "10.0.0",
+		})),
+	)
+
+	if _, err := GetE[int](config, "MISSING"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+
+	var convErr *ConversionError
+	_, err := GetE[testLevel](config, "LEVEL")
+	if !errors.As(err, &convErr) || convErr.Type != "config.testLevel" || convErr.Value != "medium" {
+		t.Fatalf("Expected *ConversionError for LEVEL, got %v", err)
+	}
+	if err.Error() != `failed to convert LEVEL="medium" to config.testLevel: unknown level "medium"` {
+		t.Errorf("Unexpected error message %q", err.Error())
+	}
+
+	if _, err := GetE[int8](config, "SMALL"); !errors.Is(err, strconv.ErrRange) {
+		t.Errorf("Expected strconv.ErrRange, got %v", err)
+	}
+	if _, err := GetE[[]int](config, "PORTS"); err == nil || !strings.Contains(err.Error(), "element 1: invalid syntax") {
+		t.Errorf("Expected the failing element to be named, got %v", err)
+	}
+	if _, err := GetE[netip.Addr](config, "ADDR"); !errors.As(err, &convErr) {
+		t.Errorf("Expected *ConversionError from UnmarshalText, got %v", err)
+	}
+	if _, err := GetE[chan int](config, "LEVEL"); !errors.Is(err, errUnsupportedType) {
+		t.Errorf("Expected an unsupported type error, got %v", err)
+	}
+}
+
+func TestGetNilConfig(t *testing.T) {
+	os.Setenv("GENERIC_LEVEL", "low")
+	defer os.Unsetenv("GENERIC_LEVEL")
+
+	if value := Get[testLevel](nil, "GENERIC_LEVEL"); value != 1 {
+		t.Errorf("Expected GENERIC_LEVEL=1 from the environment, got %d", value)
+	}
+	if _, err := GetE[testLevel](nil, "GENERIC_MISSING"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestUnmarshalDecoders(t *testing.T) {
+	type Settings struct {
+		Level  testLevel    `config:"LEVEL"`
+		Addr   netip.Addr   `config:"ADDR"` // A struct read from a single key
+		Hosts  []netip.Addr `config:"HOSTS"`
+		Limits map[string]int
+	}
+
+	config := NewWithOptions("",
+		WithIsolated(),
+		WithSources(DefaultsSource(map[string]interface{}{
+			"level":  "low",
+			"addr":   "::1",
+			"hosts":  "10.0.0.1, 10.0.0.2",
+			"limits": "cpu=2,mem=512",
+		})),
+	)
+
+	var settings Settings
+	if err := config.Unmarshal(&settings); err != nil {
+		t.Fatalf("Failed to unmarshal: %v", err)
+	}
+	if settings.Level != 1 || settings.Addr != netip.IPv6Loopback() || len(settings.Hosts) != 2 {
+		t.Errorf("Unexpected settings %+v", settings)
+	}
+	if !reflect.DeepEqual(settings.Limits, map[string]int{"cpu": 2, "mem": 512}) {
+		t.Errorf("Expected LIMITS map, got %v", settings.Limits)
+	}
+}
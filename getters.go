@@ -0,0 +1,149 @@
+package config
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Typed getters trim surrounding whitespace before parsing, and return the
+// default when the value is missing, blank or cannot be parsed.
+
+// Float64 retrieves a float64 environment variable with optional default value
+func (c *Config) Float64(key string, defaultValue ...float64) float64 {
+	value, _ := c.lookup(key)
+	return typedValue(value, parseFloat64, defaultValue)
+}
+
+// Int64 retrieves an int64 environment variable with optional default value
+func (c *Config) Int64(key string, defaultValue ...int64) int64 {
+	value, _ := c.lookup(key)
+	return typedValue(value, parseInt64, defaultValue)
+}
+
+// Uint64 retrieves a uint64 environment variable with optional default value
+func (c *Config) Uint64(key string, defaultValue ...uint64) uint64 {
+	value, _ := c.lookup(key)
+	return typedValue(value, parseUint64, defaultValue)
+}
+
+// Duration retrieves a time.Duration environment variable with optional default value
+// Values use time.ParseDuration syntax like "1m30s", plain numbers are seconds.
+func (c *Config) Duration(key string, defaultValue ...time.Duration) time.Duration {
+	value, _ := c.lookup(key)
+	return typedValue(value, parseDuration, defaultValue)
+}
+
+// Time retrieves a time.Time environment variable with optional default value
+// Values are parsed as RFC3339, then with the layouts set by WithTimeLayouts.
+func (c *Config) Time(key string, defaultValue ...time.Time) time.Time {
+	value, _ := c.lookup(key)
+	return typedValue(value, timeParser(c.timeLayouts), defaultValue)
+}
+
+// Float64 retrieves a float64 environment variable with optional default value
+func Float64(key string, defaultValue ...float64) float64 {
+	return typedValue(os.Getenv(key), parseFloat64, defaultValue)
+}
+
+// Int64 retrieves an int64 environment variable with optional default value
+func Int64(key string, defaultValue ...int64) int64 {
+	return typedValue(os.Getenv(key), parseInt64, defaultValue)
+}
+
+// Uint64 retrieves a uint64 environment variable with optional default value
+func Uint64(key string, defaultValue ...uint64) uint64 {
+	return typedValue(os.Getenv(key), parseUint64, defaultValue)
+}
+
+// Duration retrieves a time.Duration environment variable with optional default value
+// Values use time.ParseDuration syntax like "1m30s", plain numbers are seconds.
+func Duration(key string, defaultValue ...time.Duration) time.Duration {
+	return typedValue(os.Getenv(key), parseDuration, defaultValue)
+}
+
+// Time retrieves a time.Time environment variable in RFC3339 format with optional default value
+func Time(key string, defaultValue ...time.Time) time.Time {
+	return typedValue(os.Getenv(key), timeParser(nil), defaultValue)
+}
+
+// TimeLayout retrieves a time.Time environment variable with optional default value
+// Values are parsed as RFC3339, then with each of layouts, like Config.Time
+// with WithTimeLayouts.
+func TimeLayout(key string, layouts []string, defaultValue ...time.Time) time.Time {
+	return typedValue(os.Getenv(key), timeParser(layouts), defaultValue)
+}
+
+// typedValue parses value, falling back to the default when it is blank or invalid
+func typedValue[T any](value string, parse func(string) (T, error), defaultValue []T) T {
+	if strings.TrimSpace(value) != "" {
+		if parsed, err := parse(value); err == nil {
+			return parsed
+		}
+	}
+	if len(defaultValue) > 0 {
+		return defaultValue[0]
+	}
+	var zero T
+	return zero
+}
+
+func parseInt(value string) (int, error) {
+	return strconv.Atoi(strings.TrimSpace(value))
+}
+
+func parseInt64(value string) (int64, error) {
+	return strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+}
+
+func parseUint64(value string) (uint64, error) {
+	return strconv.ParseUint(strings.TrimSpace(value), 10, 64)
+}
+
+func parseFloat64(value string) (float64, error) {
+	return strconv.ParseFloat(strings.TrimSpace(value), 64)
+}
+
+// parseBool parses the boolean forms accepted by Bool
+func parseBool(value string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "true", "1", "yes", "on":
+		return true, nil
+	case "false", "0", "no", "off":
+		return false, nil
+	}
+	return false, fmt.Errorf("invalid boolean %q", value)
+}
+
+// parseDuration parses a time.ParseDuration string or a number of seconds
+func parseDuration(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		d := seconds * float64(time.Second)
+		if math.IsNaN(d) || d > math.MaxInt64 || d < math.MinInt64 {
+			return 0, fmt.Errorf("duration %q out of range", value)
+		}
+		return time.Duration(d), nil
+	}
+	return time.ParseDuration(value)
+}
+
+// timeParser returns a parser trying RFC3339 and then each of layouts
+func timeParser(layouts []string) func(string) (time.Time, error) {
+	return func(value string) (time.Time, error) {
+		value = strings.TrimSpace(value)
+		t, err := time.Parse(time.RFC3339, value)
+		if err == nil {
+			return t, nil
+		}
+		for _, layout := range layouts {
+			if t, layoutErr := time.Parse(layout, value); layoutErr == nil {
+				return t, nil
+			}
+		}
+		return time.Time{}, err
+	}
+}
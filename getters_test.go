@@ -0,0 +1,146 @@
+package config
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestTypedGetters(t *testing.T) {
+	config := NewWithOptions("",
+		WithIsolated(),
+		WithTimeLayouts(time.DateOnly, "02/01/2006 15:04"),
+		WithSources(DefaultsSource(map[string]interface{}{
+			"ratio":       " 0.75 ",
+			"big":         "9223372036854775807",
+			"negative":    "-42",
+			"size":        "18446744073709551615",
+			"timeout":     "1m30s",
+			"retry":       " 15 ",
+			"backoff":     "0.5",
+			"started":     "2024-05-01T10:30:00+07:00",
+			"released":    "2024-12-19",
+			"deadline":    "31/12/2024 23:59",
+			"port":        " 8080 ",
+			"debug":       " yes ",
+			"invalid":     "abc",
+			"blank":       "   ",
+			"huge":        "1e300",
+			"not_a_float": "1.2.3",
+		})),
+	)
+
+	if value := config.Float64("RATIO"); value != 0.75 {
+		t.Errorf("Expected RATIO=0.75, got %v", value)
+	}
+	if value := config.Int64("BIG"); value != 9223372036854775807 {
+		t.Errorf("Expected BIG=9223372036854775807, got %d", value)
+	}
+	if value := config.Int64("NEGATIVE"); value != -42 {
+		t.Errorf("Expected NEGATIVE=-42, got %d", value)
+	}
+	if value := config.Uint64("SIZE"); value != 18446744073709551615 {
+		t.Errorf("Expected SIZE=18446744073709551615, got %d", value)
+	}
+	if value := config.Uint64("NEGATIVE", 7); value != 7 {
+		t.Errorf("Expected the default for a negative Uint64, got %d", value)
+	}
+
+	durations := map[string]time.Duration{
+		"TIMEOUT": 90 * time.Second,
+		"RETRY":   15 * time.Second,
+		"BACKOFF": 500 * time.Millisecond,
+	}
+	for key, expected := range durations {
+		if value := config.Duration(key); value != expected {
+			t.Errorf("Expected %s=%v, got %v", key, expected, value)
+		}
+	}
+	if value := config.Duration("HUGE", time.Minute); value != time.Minute {
+		t.Errorf("Expected the default for an out of range Duration, got %v", value)
+	}
+
+	started := time.Date(2024, 5, 1, 3, 30, 0, 0, time.UTC)
+	if value := config.Time("STARTED"); !value.Equal(started) {
+		t.Errorf("Expected STARTED=%v, got %v", started, value)
+	}
+	if value := config.Time("RELEASED"); !value.Equal(time.Date(2024, 12, 19, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected RELEASED=2024-12-19, got %v", value)
+	}
+	if value := config.Time("DEADLINE"); !value.Equal(time.Date(2024, 12, 31, 23, 59, 0, 0, time.UTC)) {
+		t.Errorf("Expected DEADLINE=2024-12-31 23:59, got %v", value)
+	}
+
+	// Surrounding whitespace is ignored by every typed getter
+	if value := config.Int("PORT"); value != 8080 {
+		t.Errorf("Expected PORT=8080, got %d", value)
+	}
+	if !config.Bool("DEBUG") {
+		t.Errorf("Expected DEBUG=true, got false")
+	}
+
+	// Invalid, blank and missing values fall back to the default
+	for _, key := range []string{"INVALID", "BLANK", "MISSING", "NOT_A_FLOAT"} {
+		if value := config.Float64(key, 1.5); value != 1.5 {
+			t.Errorf("Expected the default for %s, got %v", key, value)
+		}
+	}
+	if value := config.Int64("INVALID"); value != 0 {
+		t.Errorf("Expected zero for an invalid Int64, got %d", value)
+	}
+	fallback := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	if value := config.Time("INVALID", fallback); !value.Equal(fallback) {
+		t.Errorf("Expected the default for an invalid Time, got %v", value)
+	}
+}
+
+func TestGlobalTypedGetters(t *testing.T) {
+	os.Setenv("GLOBAL_RATIO", "2.5")
+	os.Setenv("GLOBAL_COUNT", " -12 ")
+	os.Setenv("GLOBAL_SIZE", "42")
+	os.Setenv("GLOBAL_TIMEOUT", "250ms")
+	os.Setenv("GLOBAL_STARTED", "2024-05-01T10:30:00Z")
+	defer os.Unsetenv("GLOBAL_RATIO")
+	defer os.Unsetenv("GLOBAL_COUNT")
+	defer os.Unsetenv("GLOBAL_SIZE")
+	defer os.Unsetenv("GLOBAL_TIMEOUT")
+	defer os.Unsetenv("GLOBAL_STARTED")
+
+	if value := Float64("GLOBAL_RATIO"); value != 2.5 {
+		t.Errorf("Expected GLOBAL_RATIO=2.5, got %v", value)
+	}
+	if value := Int64("GLOBAL_COUNT"); value != -12 {
+		t.Errorf("Expected GLOBAL_COUNT=-12, got %d", value)
+	}
+	if value := Int("GLOBAL_COUNT"); value != -12 {
+		t.Errorf("Expected Int GLOBAL_COUNT=-12, got %d", value)
+	}
+	if value := Uint64("GLOBAL_SIZE"); value != 42 {
+		t.Errorf("Expected GLOBAL_SIZE=42, got %d", value)
+	}
+	if value := Duration("GLOBAL_TIMEOUT"); value != 250*time.Millisecond {
+		t.Errorf("Expected GLOBAL_TIMEOUT=250ms, got %v", value)
+	}
+	if value := Time("GLOBAL_STARTED"); !value.Equal(time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)) {
+		t.Errorf("Expected GLOBAL_STARTED=2024-05-01T10:30:00Z, got %v", value)
+	}
+	if value := Duration("GLOBAL_MISSING", time.Second); value != time.Second {
+		t.Errorf("Expected the default for a missing Duration, got %v", value)
+	}
+
+	os.Setenv("GLOBAL_RELEASED", "19/12/2024")
+	defer os.Unsetenv("GLOBAL_RELEASED")
+	layouts := []string{"2006-01-02", "02/01/2006"}
+	if value := TimeLayout("GLOBAL_RELEASED", layouts); !value.Equal(time.Date(2024, 12, 19, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected GLOBAL_RELEASED from the second layout, got %v", value)
+	}
+	if value := TimeLayout("GLOBAL_STARTED", layouts); !value.Equal(time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)) {
+		t.Errorf("Expected RFC3339 to be tried first, got %v", value)
+	}
+	if value := Time("GLOBAL_RELEASED"); !value.IsZero() {
+		t.Errorf("Expected Time to accept only RFC3339, got %v", value)
+	}
+	if _, err := TimeLayoutE("GLOBAL_RELEASED", []string{"2006-01-02"}); err == nil {
+		t.Error("Expected a conversion error without a matching layout")
+	}
+}
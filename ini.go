@@ -0,0 +1,85 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// loadINIConfig loads configuration from INI data
+//
+// Section names become key prefixes, so "host" in [database] is loaded as
+// database.host (DATABASE_HOST). Dotted section names like [database.pool]
+// nest further. Lines starting with ; or # are comments, keys and values may
+// be separated by = or :, and unquoted values may end with a ; comment.
+func loadINIConfig(data []byte, strict bool) (map[string]interface{}, error) {
+	config := make(map[string]interface{})
+	seen := make(map[string]int)
+	section := ""
+
+	lines := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	for i, raw := range lines {
+		lineNum := i + 1
+		line := strings.TrimSpace(raw)
+
+		// Skip empty lines and comments
+		if line == "" || line[0] == ';' || line[0] == '#' {
+			continue
+		}
+
+		if line[0] == '[' {
+			end := strings.IndexByte(line, ']')
+			if end < 0 {
+				if strict {
+					return nil, &ParseError{Line: lineNum, Column: 1, Text: raw, Reason: "unterminated section header"}
+				}
+				continue
+			}
+			section = strings.TrimSpace(line[1:end])
+			continue
+		}
+
+		sep := strings.IndexAny(line, "=:")
+		if sep <= 0 {
+			if strict {
+				return nil, &ParseError{Line: lineNum, Column: 1, Text: raw, Reason: "missing '=' or ':' in assignment"}
+			}
+			continue
+		}
+
+		key := strings.TrimSpace(line[:sep])
+		if section != "" {
+			key = section + "." + key
+		}
+		if first, ok := seen[key]; ok && strict {
+			return nil, &ParseError{
+				Line:   lineNum,
+				Column: 1,
+				Text:   raw,
+				Reason: fmt.Sprintf("duplicate key %s, first defined on line %d", key, first),
+			}
+		}
+		seen[key] = lineNum
+		config[key] = iniValue(strings.TrimSpace(line[sep+1:]))
+	}
+
+	return config, nil
+}
+
+// iniValue removes a trailing ; comment and then matching quotes from an INI value
+// A quoted value may contain ";", the comment starts after the closing quote.
+func iniValue(value string) string {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') {
+		if end := strings.IndexByte(value[1:], value[0]) + 1; end > 0 {
+			rest := strings.TrimSpace(value[end+1:])
+			if rest == "" || rest[0] == ';' {
+				return value[1:end]
+			}
+		}
+	}
+	for i := 1; i < len(value); i++ {
+		if value[i] == ';' && (value[i-1] == ' ' || value[i-1] == '\t') {
+			return strings.TrimSpace(value[:i])
+		}
+	}
+	return value
+}
@@ -0,0 +1,223 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// literal is a string value that interpolate leaves as is, e.g. a single-quoted .env value
+// Loaders return it only to callers that interpolate, see plainValues.
+type literal string
+
+// plainValues replaces literal values with plain strings for callers that do not interpolate
+func plainValues(config map[string]interface{}) map[string]interface{} {
+	for key, value := range config {
+		if v, ok := value.(literal); ok {
+			config[key] = string(v)
+		}
+	}
+	return config
+}
+
+// interpolate expands references in the string values of a flattened config map
+//
+// Supported forms are ${KEY}, ${KEY:-default} (used when KEY is unset or empty),
+// ${KEY:?message} (an error when KEY is unset or empty) and $$ for a literal "$".
+// References resolve against the other keys of config first and fall back to
+// lookup, which covers earlier layers and the process environment. Keys may be
+// written as environment names (DATABASE_HOST) or dotted paths (database.host).
+// Literal values are not expanded and become plain strings.
+func interpolate(config map[string]interface{}, lookup func(string) (string, bool)) error {
+	in := &interpolator{
+		config:   config,
+		paths:    make(map[string]string, len(config)),
+		lookup:   lookup,
+		resolved: make(map[string]string),
+		active:   make(map[string]bool),
+	}
+	for path := range config {
+		in.paths[toEnvKey(path)] = path
+	}
+
+	for path, value := range config {
+		switch value.(type) {
+		case string, literal:
+		default:
+			continue
+		}
+		expanded, err := in.resolve(toEnvKey(path))
+		if err != nil {
+			return err
+		}
+		config[path] = expanded
+	}
+	return nil
+}
+
+// interpolator resolves references between the keys of one config map
+type interpolator struct {
+	config   map[string]interface{}
+	paths    map[string]string // Environment name to original key
+	lookup   func(string) (string, bool)
+	resolved map[string]string
+	active   map[string]bool
+	stack    []string
+}
+
+// resolve returns the expanded value of the key with the given environment name
+func (in *interpolator) resolve(envKey string) (string, error) {
+	if value, ok := in.resolved[envKey]; ok {
+		return value, nil
+	}
+
+	path := in.paths[envKey]
+	if in.active[envKey] {
+		return "", in.cycleError(path)
+	}
+
+	if value, ok := in.config[path].(literal); ok {
+		in.resolved[envKey] = string(value)
+		return string(value), nil
+	}
+	raw, ok := in.config[path].(string)
+	if !ok {
+		return formatValue(in.config[path]), nil
+	}
+
+	in.active[envKey] = true
+	in.stack = append(in.stack, path)
+	value, err := in.expand(raw)
+	in.stack = in.stack[:len(in.stack)-1]
+	delete(in.active, envKey)
+	if err != nil {
+		return "", err
+	}
+
+	in.resolved[envKey] = value
+	return value, nil
+}
+
+// expand replaces every reference in s
+func (in *interpolator) expand(s string) (string, error) {
+	if !strings.Contains(s, "$") {
+		return s, nil
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '$' || i+1 >= len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+
+		switch s[i+1] {
+		case '$':
+			b.WriteByte('$')
+			i++
+		case '{':
+			end := matchingBrace(s, i+1)
+			if end < 0 {
+				return "", fmt.Errorf("failed to interpolate %s: unterminated reference in %q", in.current(), s)
+			}
+			value, err := in.reference(s[i+2 : end])
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(value)
+			i = end
+		default:
+			b.WriteByte('$')
+		}
+	}
+	return b.String(), nil
+}
+
+// reference evaluates the inside of a ${...} expression
+func (in *interpolator) reference(expr string) (string, error) {
+	name, operand, op := expr, "", ""
+	if i := strings.Index(expr, ":"); i >= 0 && i+1 < len(expr) && (expr[i+1] == '-' || expr[i+1] == '?') {
+		name, op, operand = expr[:i], expr[i:i+2], expr[i+2:]
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", fmt.Errorf("failed to interpolate %s: empty reference", in.current())
+	}
+
+	value, err := in.value(name)
+	if err != nil {
+		return "", err
+	}
+	if value != "" {
+		return value, nil
+	}
+
+	switch op {
+	case ":-":
+		return in.expand(operand)
+	case ":?":
+		message, err := in.expand(operand)
+		if err != nil {
+			return "", err
+		}
+		if message == "" {
+			message = "is required"
+		}
+		return "", fmt.Errorf("failed to interpolate %s: %s %s", in.current(), name, message)
+	}
+	return "", nil
+}
+
+// value looks up a referenced key in the same config, then through lookup
+func (in *interpolator) value(name string) (string, error) {
+	envKey := toEnvKey(name)
+	if _, ok := in.paths[envKey]; ok {
+		return in.resolve(envKey)
+	}
+	if value, ok := in.lookup(name); ok {
+		return value, nil
+	}
+	if envKey != name {
+		if value, ok := in.lookup(envKey); ok {
+			return value, nil
+		}
+	}
+	return "", nil
+}
+
+// current returns the key being expanded
+func (in *interpolator) current() string {
+	if len(in.stack) == 0 {
+		return ""
+	}
+	return in.stack[len(in.stack)-1]
+}
+
+// cycleError describes a reference loop ending at path
+func (in *interpolator) cycleError(path string) error {
+	start := 0
+	for i, key := range in.stack {
+		if toEnvKey(key) == toEnvKey(path) {
+			start = i
+			break
+		}
+	}
+	loop := append(append([]string(nil), in.stack[start:]...), path)
+	return fmt.Errorf("failed to interpolate %s: reference cycle %s", loop[0], strings.Join(loop, " -> "))
+}
+
+// matchingBrace returns the index of the brace closing the one at open, or -1
+func matchingBrace(s string, open int) int {
+	depth := 0
+	for i := open; i < len(s); i++ {
+		switch s[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
@@ -0,0 +1,101 @@
+package config
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestInterpolate(t *testing.T) {
+	os.Setenv("INTERP_OS_HOST", "os.local")
+	defer os.Unsetenv("INTERP_OS_HOST")
+
+	config := map[string]interface{}{
+		"HOST":           "db.local",
+		"URL":            "postgres://${HOST}:${PORT:-5432}/app",
+		"database.name":  "app",
+		"DSN":            "${database.name}@${DATABASE_NAME}",
+		"FROM_OS":        "${INTERP_OS_HOST}",
+		"DEFAULT_NESTED": "${MISSING:-${HOST}}",
+		"PRICE":          "$$5 and $HOME",
+		"PORT_NUMBER":    8080,
+		"PORT_REF":       "${PORT_NUMBER}",
+	}
+	if err := interpolate(config, os.LookupEnv); err != nil {
+		t.Fatalf("Failed to interpolate: %v", err)
+	}
+
+	expected := map[string]interface{}{
+		"URL":            "postgres://db.local:5432/app",
+		"DSN":            "app@app",
+		"FROM_OS":        "os.local",
+		"DEFAULT_NESTED": "db.local",
+		"PRICE":          "$5 and $HOME",
+		"PORT_NUMBER":    8080,
+		"PORT_REF":       "8080",
+	}
+	for key, value := range expected {
+		if config[key] != value {
+			t.Errorf("Expected %s=%v, got %v", key, value, config[key])
+		}
+	}
+}
+
+func TestInterpolateErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		config map[string]interface{}
+		want   string
+	}{
+		{
+			"required",
+			map[string]interface{}{"DSN": "${DB_PASSWORD:?must be set}"},
+			"DB_PASSWORD must be set",
+		},
+		{
+			"cycle",
+			map[string]interface{}{"A": "${B}", "B": "${C}", "C": "x${A}"},
+			" -> ",
+		},
+		{
+			"self reference",
+			map[string]interface{}{"A": "${A}"},
+			"A -> A",
+		},
+		{
+			"unterminated",
+			map[string]interface{}{"A": "${B"},
+			"unterminated reference",
+		},
+	}
+
+	for _, test := range tests {
+		err := interpolate(test.config, func(string) (string, bool) { return "", false })
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%s: expected error containing %q, got %v", test.name, test.want, err)
+		}
+	}
+}
+
+func TestInterpolateLayers(t *testing.T) {
+	err := createTestFile("interp_base.yaml", "interp:\n  host: base.local\n")
+	if err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	defer cleanupTestFile("interp_base.yaml")
+
+	err = createTestFile("interp_app.env", "INTERP_URL=http://${INTERP_HOST}:${INTERP_PORT}\nINTERP_PORT=9000\n")
+	if err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	defer cleanupTestFile("interp_app.env")
+
+	config := NewWithOptions("", WithIsolated(), WithSources(
+		FileSource("interp_base.yaml"),
+		FileSource("interp_app.env"),
+	))
+
+	if config.Str("INTERP_URL") != "http://base.local:9000" {
+		t.Errorf("Expected INTERP_URL=http://base.local:9000, got %s", config.Str("INTERP_URL"))
+	}
+}
@@ -0,0 +1,48 @@
+package config
+
+import "strings"
+
+// Keys returns the environment names of the keys this Config loaded, sorted
+// Only keys from the config file and the sources added with WithSources are
+// listed, not the rest of the process environment. A view returned by Sub
+// lists the keys below its key, named relative to it.
+func (c *Config) Keys() []string {
+	return sortedKeys(c.LoadedAll())
+}
+
+// LoadedAll returns the values this Config loaded, keyed by environment name
+// Unlike All it leaves out the process environment, except for values an
+// EnvSource loaded. The map is a copy, changing it does not affect c.
+func (c *Config) LoadedAll() map[string]string {
+	root := c
+	if c.root != nil {
+		root = c.root
+	}
+	loaded := make(map[string]string)
+	for key, value := range root.state.Load().values {
+		if name, ok := strings.CutPrefix(key, c.prefix); ok && name != "" {
+			loaded[name] = value
+		}
+	}
+	return loaded
+}
+
+// Has reports whether this Config loaded key, even with an empty value
+// Keys are matched like the getters, so "database.host" and DATABASE_HOST
+// are the same key. Values only in the process environment are not counted.
+func (c *Config) Has(key string) bool {
+	if c.root != nil {
+		return c.root.Has(c.rootKey(key))
+	}
+	_, ok := c.state.Load().values[toEnvKey(key)]
+	return ok
+}
+
+// IsSet reports whether this Config loaded key with a non-empty value
+// An empty string or null in a config file counts as not set.
+func (c *Config) IsSet(key string) bool {
+	if c.root != nil {
+		return c.root.IsSet(c.rootKey(key))
+	}
+	return c.state.Load().values[toEnvKey(key)] != ""
+}
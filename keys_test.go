@@ -0,0 +1,64 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	content := "server:\n  port: 8080\n  host: \"\"\nname: demo\nnone: null\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	os.Setenv("KEYS_TEST_EXTRA", "1")
+	defer os.Unsetenv("KEYS_TEST_EXTRA")
+
+	config := NewWithOptions(path, WithIsolated(), WithEnvFallback())
+
+	expected := []string{"NAME", "NONE", "SERVER_HOST", "SERVER_PORT"}
+	if keys := config.Keys(); !reflect.DeepEqual(keys, expected) {
+		t.Errorf("Expected keys %v, got %v", expected, keys)
+	}
+	loaded := map[string]string{"NAME": "demo", "NONE": "", "SERVER_HOST": "", "SERVER_PORT": "8080"}
+	if all := config.LoadedAll(); !reflect.DeepEqual(all, loaded) {
+		t.Errorf("Expected %v, got %v", loaded, all)
+	}
+
+	// The environment is readable but not part of what this Config loaded
+	if value := config.Str("KEYS_TEST_EXTRA"); value != "1" {
+		t.Errorf("Expected the environment fallback, got %q", value)
+	}
+	if config.Has("KEYS_TEST_EXTRA") || config.IsSet("KEYS_TEST_EXTRA") {
+		t.Error("Expected an environment-only key not to be loaded")
+	}
+
+	cases := []struct {
+		key      string
+		has, set bool
+	}{
+		{"server.port", true, true},
+		{"SERVER_PORT", true, true},
+		{"server.host", true, false},
+		{"none", true, false},
+		{"missing", false, false},
+	}
+	for _, tc := range cases {
+		if has := config.Has(tc.key); has != tc.has {
+			t.Errorf("Expected Has(%q) = %v", tc.key, tc.has)
+		}
+		if set := config.IsSet(tc.key); set != tc.set {
+			t.Errorf("Expected IsSet(%q) = %v", tc.key, tc.set)
+		}
+	}
+
+	server := config.Sub("server")
+	if keys := server.Keys(); !reflect.DeepEqual(keys, []string{"HOST", "PORT"}) {
+		t.Errorf("Expected the view's keys, got %v", keys)
+	}
+	if !server.IsSet("port") || server.Has("name") {
+		t.Error("Expected the view to check keys below server")
+	}
+}
@@ -0,0 +1,194 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// defaultSeparator separates list elements unless WithSeparator sets another
+const defaultSeparator = ","
+
+// StrSlice retrieves a list environment variable with optional default value
+// Elements are separated by commas, or the separator set with WithSeparator,
+// and trimmed. Elements containing the separator can be quoted: `"a, b", c`.
+// Arrays in JSON, YAML and TOML files are joined with commas.
+func (c *Config) StrSlice(key string, defaultValue ...[]string) []string {
+	value, _ := c.lookup(key)
+	return typedValue(value, listParser(c.separator()), defaultValue)
+}
+
+// IntSlice retrieves a list of integers with optional default value
+// The default is returned if any element is not an integer.
+func (c *Config) IntSlice(key string, defaultValue ...[]int) []int {
+	value, _ := c.lookup(key)
+	return typedValue(value, intListParser(c.separator()), defaultValue)
+}
+
+// StringMap retrieves a map with optional default value
+// A value like "region=eu,tier=web" is split into pairs. If key has no value,
+// the nested keys loaded below it are returned instead, so for a file with
+// {"labels": {"app": "web"}} StringMap("labels") returns {"app": "web"}.
+func (c *Config) StringMap(key string, defaultValue ...map[string]string) map[string]string {
+	if value, _ := c.lookup(key); strings.TrimSpace(value) != "" {
+		return typedValue(value, mapParser(c.separator()), defaultValue)
+	}
+	if nested := c.nestedValues(key); len(nested) > 0 {
+		return nested
+	}
+	return typedValue("", mapParser(c.separator()), defaultValue)
+}
+
+// separator returns the list separator of the Config
+func (c *Config) separator() string {
+	if c.listSeparator != "" {
+		return c.listSeparator
+	}
+	return defaultSeparator
+}
+
+// nestedValues returns the loaded values below key, keyed by the rest of their original path
+func (c *Config) nestedValues(key string) map[string]string {
+	if c.root != nil {
+		return c.root.nestedValues(c.rootKey(key))
+	}
+	prefix := toEnvKey(key) + "_"
+	nested := make(map[string]string)
+	for path, value := range c.state.Load().config {
+		if len(path) <= len(prefix) || toEnvKey(path[:len(prefix)]) != prefix {
+			continue
+		}
+		nested[path[len(prefix):]] = formatValue(value)
+	}
+	return nested
+}
+
+// StrSlice retrieves a comma-separated list environment variable with optional default value
+func StrSlice(key string, defaultValue ...[]string) []string {
+	return typedValue(os.Getenv(key), listParser(defaultSeparator), defaultValue)
+}
+
+// IntSlice retrieves a comma-separated list of integers with optional default value
+func IntSlice(key string, defaultValue ...[]int) []int {
+	return typedValue(os.Getenv(key), intListParser(defaultSeparator), defaultValue)
+}
+
+// StringMap retrieves a map written as "key=value,key=value" with optional default value
+func StringMap(key string, defaultValue ...map[string]string) map[string]string {
+	return typedValue(os.Getenv(key), mapParser(defaultSeparator), defaultValue)
+}
+
+// listParser returns a parser splitting lists on sep
+func listParser(sep string) func(string) ([]string, error) {
+	return func(value string) ([]string, error) {
+		return splitList(value, sep)
+	}
+}
+
+// intListParser returns a parser for lists of integers separated by sep
+func intListParser(sep string) func(string) ([]int, error) {
+	return func(value string) ([]int, error) {
+		parts, err := splitList(value, sep)
+		if err != nil {
+			return nil, err
+		}
+		ints := make([]int, len(parts))
+		for i, part := range parts {
+			if ints[i], err = parseInt(part); err != nil {
+				return nil, fmt.Errorf("element %d: %w", i, err)
+			}
+		}
+		return ints, nil
+	}
+}
+
+// mapParser returns a parser for "key=value" pairs separated by sep
+func mapParser(sep string) func(string) (map[string]string, error) {
+	return func(value string) (map[string]string, error) {
+		parts, err := splitList(value, sep)
+		if err != nil {
+			return nil, err
+		}
+		pairs := make(map[string]string, len(parts))
+		for _, part := range parts {
+			k, v, ok := strings.Cut(part, "=")
+			if !ok {
+				return nil, fmt.Errorf("missing '=' in %q", part)
+			}
+			pairs[strings.TrimSpace(k)] = strings.TrimSpace(v)
+		}
+		return pairs, nil
+	}
+}
+
+// splitList splits value on sep, trimming unquoted elements
+// Elements wrapped in double quotes may contain sep and Go escapes like \",
+// elements wrapped in single quotes are taken literally.
+func splitList(value, sep string) ([]string, error) {
+	var parts []string
+	rest := value
+	for {
+		trimmed := strings.TrimLeft(rest, " \t")
+		if trimmed != "" && (trimmed[0] == '"' || trimmed[0] == '\'') {
+			element, after, err := readQuoted(trimmed)
+			if err != nil {
+				return nil, err
+			}
+			parts = append(parts, element)
+
+			after = strings.TrimLeft(after, " \t")
+			if after == "" {
+				return parts, nil
+			}
+			if !strings.HasPrefix(after, sep) {
+				return nil, fmt.Errorf("unexpected characters after quoted element in %q", value)
+			}
+			rest = after[len(sep):]
+			continue
+		}
+
+		element, after, found := strings.Cut(rest, sep)
+		parts = append(parts, strings.TrimSpace(element))
+		if !found {
+			return parts, nil
+		}
+		rest = after
+	}
+}
+
+// readQuoted reads a quoted element from the start of s and returns it with the text after it
+func readQuoted(s string) (string, string, error) {
+	quote := s[0]
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if quote == '"' {
+				i++
+			}
+		case quote:
+			if quote == '\'' {
+				return s[1:i], s[i+1:], nil
+			}
+			element, err := strconv.Unquote(s[:i+1])
+			if err != nil {
+				return "", "", fmt.Errorf("invalid quoted element %s", s[:i+1])
+			}
+			return element, s[i+1:], nil
+		}
+	}
+	return "", "", fmt.Errorf("unterminated quoted element %s", s)
+}
+
+// joinList joins list elements with commas, quoting those splitList would not return unchanged
+func joinList(elements []string) string {
+	quoted := make([]string, len(elements))
+	for i, element := range elements {
+		if element != strings.TrimSpace(element) || strings.Contains(element, defaultSeparator) ||
+			strings.HasPrefix(element, `"`) || strings.HasPrefix(element, "'") {
+			element = strconv.Quote(element)
+		}
+		quoted[i] = element
+	}
+	return strings.Join(quoted, defaultSeparator)
+}
@@ -0,0 +1,142 @@
+package config
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestSplitList(t *testing.T) {
+	tests := []struct {
+		value    string
+		sep      string
+		expected []string
+	}{
+		{"a,b,c", ",", []string{"a", "b", "c"}},
+		{" a , b ,c ", ",", []string{"a", "b", "c"}},
+		{`"a, b", c`, ",", []string{"a, b", "c"}},
+		{`'x,"y"',z`, ",", []string{`x,"y"`, "z"}},
+		{`"say \"hi\"",bye`, ",", []string{`say "hi"`, "bye"}},
+		{"a,,b", ",", []string{"a", "", "b"}},
+		{"a;b c;d", ";", []string{"a", "b c", "d"}},
+		{"a::b", "::", []string{"a", "b"}},
+		{"it's,fine", ",", []string{"it's", "fine"}},
+	}
+
+	for _, test := range tests {
+		got, err := splitList(test.value, test.sep)
+		if err != nil {
+			t.Errorf("splitList(%q) returned error: %v", test.value, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("splitList(%q, %q) = %q, expected %q", test.value, test.sep, got, test.expected)
+		}
+	}
+
+	for _, value := range []string{`"unterminated, a`, `"a" b, c`} {
+		if _, err := splitList(value, ","); err == nil {
+			t.Errorf("Expected splitList(%q) to fail", value)
+		}
+	}
+}
+
+func TestSliceAndMapGetters(t *testing.T) {
+	jsonContent := `{
+		"lists": {
+			"tags": ["a,b", "c", " padded "],
+			"ports": [80, 443],
+			"matrix": [[1, 2], [3]],
+			"mixed": [1, {"x": 2}],
+			"servers": [{"host": "alpha"}, {"host": "beta"}],
+			"labels": {"app": "web", "tier": "db"},
+			"pairs": "region=eu, zone = a"
+		}
+	}`
+	err := createTestFile("lists_test.json", jsonContent)
+	if err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	defer cleanupTestFile("lists_test.json")
+
+	config := NewWithOptions("lists_test.json", WithIsolated())
+
+	if value := config.StrSlice("LISTS_TAGS"); !reflect.DeepEqual(value, []string{"a,b", "c", " padded "}) {
+		t.Errorf("Expected LISTS_TAGS to round trip, got %q", value)
+	}
+	if value := config.IntSlice("LISTS_PORTS"); !reflect.DeepEqual(value, []int{80, 443}) {
+		t.Errorf("Expected LISTS_PORTS=[80 443], got %v", value)
+	}
+	if value := config.IntSlice("LISTS_TAGS", []int{1}); !reflect.DeepEqual(value, []int{1}) {
+		t.Errorf("Expected the default for a non-integer list, got %v", value)
+	}
+
+	// Arrays holding arrays or objects are addressed by index
+	addressed := map[string]string{
+		"LISTS_MATRIX_0":       "1,2",
+		"LISTS_MATRIX_1":       "3",
+		"LISTS_MIXED_0":        "1",
+		"LISTS_MIXED_1_X":      "2",
+		"LISTS_SERVERS_1_HOST": "beta",
+	}
+	for key, expected := range addressed {
+		if value := config.Str(key); value != expected {
+			t.Errorf("Expected %s=%s, got %s", key, expected, value)
+		}
+	}
+
+	if value := config.StringMap("lists.labels"); !reflect.DeepEqual(value, map[string]string{"app": "web", "tier": "db"}) {
+		t.Errorf("Expected nested LISTS_LABELS map, got %v", value)
+	}
+	if value := config.StringMap("LISTS_PAIRS"); !reflect.DeepEqual(value, map[string]string{"region": "eu", "zone": "a"}) {
+		t.Errorf("Expected LISTS_PAIRS map, got %v", value)
+	}
+	fallback := map[string]string{"k": "v"}
+	if value := config.StringMap("LISTS_MISSING", fallback); !reflect.DeepEqual(value, fallback) {
+		t.Errorf("Expected the default map, got %v", value)
+	}
+}
+
+func TestWithSeparator(t *testing.T) {
+	config := NewWithOptions("",
+		WithIsolated(),
+		WithSeparator(";"),
+		WithSources(DefaultsSource(map[string]interface{}{
+			"hosts":  "a.local; b.local;'c;d'",
+			"ids":    "1;2;3",
+			"labels": "app=web;tier=db",
+		})),
+	)
+
+	if value := config.StrSlice("HOSTS"); !reflect.DeepEqual(value, []string{"a.local", "b.local", "c;d"}) {
+		t.Errorf("Expected HOSTS split on ';', got %q", value)
+	}
+	if value := config.IntSlice("IDS"); !reflect.DeepEqual(value, []int{1, 2, 3}) {
+		t.Errorf("Expected IDS=[1 2 3], got %v", value)
+	}
+	if value := config.StringMap("LABELS"); !reflect.DeepEqual(value, map[string]string{"app": "web", "tier": "db"}) {
+		t.Errorf("Expected LABELS map, got %v", value)
+	}
+}
+
+func TestGlobalSliceAndMapGetters(t *testing.T) {
+	os.Setenv("GLOBAL_LIST", `x, "y, z"`)
+	os.Setenv("GLOBAL_INTS", "4,5")
+	os.Setenv("GLOBAL_MAP", "a=1,b=2")
+	defer os.Unsetenv("GLOBAL_LIST")
+	defer os.Unsetenv("GLOBAL_INTS")
+	defer os.Unsetenv("GLOBAL_MAP")
+
+	if value := StrSlice("GLOBAL_LIST"); !reflect.DeepEqual(value, []string{"x", "y, z"}) {
+		t.Errorf("Expected GLOBAL_LIST=[x, y, z], got %q", value)
+	}
+	if value := IntSlice("GLOBAL_INTS"); !reflect.DeepEqual(value, []int{4, 5}) {
+		t.Errorf("Expected GLOBAL_INTS=[4 5], got %v", value)
+	}
+	if value := StringMap("GLOBAL_MAP"); !reflect.DeepEqual(value, map[string]string{"a": "1", "b": "2"}) {
+		t.Errorf("Expected GLOBAL_MAP map, got %v", value)
+	}
+	if value := StrSlice("GLOBAL_MISSING", []string{"d"}); !reflect.DeepEqual(value, []string{"d"}) {
+		t.Errorf("Expected the default list, got %q", value)
+	}
+}
@@ -0,0 +1,238 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// ErrNotFound is returned by the error-returning getters when a key is not set
+var ErrNotFound = errors.New("key not found")
+
+// ConversionError is returned by the error-returning getters when a value cannot be converted
+type ConversionError struct {
+	Key   string // Key passed to the getter
+	Value string // Raw value
+	Type  string // Target type, e.g. "int" or "time.Duration"
+	Err   error  // Underlying parse error
+}
+
+func (e *ConversionError) Error() string {
+	return fmt.Sprintf("failed to convert %s=%q to %s: %v", e.Key, e.Value, e.Type, e.Err)
+}
+
+func (e *ConversionError) Unwrap() error {
+	return e.Err
+}
+
+// notFound returns the error for a missing key
+func notFound(key string) error {
+	return fmt.Errorf("failed to read %s: %w", key, ErrNotFound)
+}
+
+// typedValueE parses the value of key, returning ErrNotFound or a *ConversionError on failure
+// Unlike the getters with defaults, a blank value is a conversion error rather than missing.
+func typedValueE[T any](key, value string, ok bool, parse func(string) (T, error)) (T, error) {
+	var zero T
+	if !ok {
+		return zero, notFound(key)
+	}
+	parsed, err := parse(value)
+	if err != nil {
+		// The raw value is already part of the message
+		if numErr, ok := err.(*strconv.NumError); ok {
+			err = numErr.Err
+		}
+		return zero, &ConversionError{Key: key, Value: value, Type: fmt.Sprintf("%T", zero), Err: err}
+	}
+	return parsed, nil
+}
+
+// Lookup returns the value of key and whether it is set, even if it is empty
+func (c *Config) Lookup(key string) (string, bool) {
+	return c.lookup(key)
+}
+
+// Get returns the loaded value of key with the type it was decoded with
+// JSON numbers are json.Number, YAML and TOML numbers are int, int64 or
+// float64, null is nil and arrays of scalars are []interface{}. Values from
+// .env, INI and .properties files and the environment are strings.
+//
+// Keys address nested values by their original path, e.g. "database.host"
+// or "servers.0.host", or by environment name. A key above loaded values,
+// like "database", returns them as a map[string]interface{}, or as an array
+// for an array of objects. Keys this Config did not load are read like
+// Lookup, Get returns nil if key is not set.
+func (c *Config) Get(key string) interface{} {
+	if c.root != nil {
+		return c.root.Get(c.rootKey(key))
+	}
+	state := c.state.Load()
+	if value, ok := state.typed[toEnvKey(key)]; ok {
+		return value
+	}
+	if tree := state.nestedTree(toEnvKey(key) + "_"); tree != nil {
+		return tree
+	}
+	if value, ok := c.lookup(key); ok {
+		return value
+	}
+	return nil
+}
+
+// StrE retrieves a string value, returning ErrNotFound if key is not set
+// Unlike Str, an empty value is returned as is.
+func (c *Config) StrE(key string) (string, error) {
+	value, ok := c.lookup(key)
+	if !ok {
+		return "", notFound(key)
+	}
+	return value, nil
+}
+
+// IntE retrieves an integer value, returning ErrNotFound or a *ConversionError
+func (c *Config) IntE(key string) (int, error) {
+	value, ok := c.lookup(key)
+	return typedValueE(key, value, ok, parseInt)
+}
+
+// BoolE retrieves a boolean value, returning ErrNotFound or a *ConversionError
+func (c *Config) BoolE(key string) (bool, error) {
+	value, ok := c.lookup(key)
+	return typedValueE(key, value, ok, parseBool)
+}
+
+// Float64E retrieves a float64 value, returning ErrNotFound or a *ConversionError
+func (c *Config) Float64E(key string) (float64, error) {
+	value, ok := c.lookup(key)
+	return typedValueE(key, value, ok, parseFloat64)
+}
+
+// Int64E retrieves an int64 value, returning ErrNotFound or a *ConversionError
+func (c *Config) Int64E(key string) (int64, error) {
+	value, ok := c.lookup(key)
+	return typedValueE(key, value, ok, parseInt64)
+}
+
+// Uint64E retrieves a uint64 value, returning ErrNotFound or a *ConversionError
+func (c *Config) Uint64E(key string) (uint64, error) {
+	value, ok := c.lookup(key)
+	return typedValueE(key, value, ok, parseUint64)
+}
+
+// DurationE retrieves a time.Duration value, returning ErrNotFound or a *ConversionError
+func (c *Config) DurationE(key string) (time.Duration, error) {
+	value, ok := c.lookup(key)
+	return typedValueE(key, value, ok, parseDuration)
+}
+
+// TimeE retrieves a time.Time value, returning ErrNotFound or a *ConversionError
+func (c *Config) TimeE(key string) (time.Time, error) {
+	value, ok := c.lookup(key)
+	return typedValueE(key, value, ok, timeParser(c.timeLayouts))
+}
+
+// StrSliceE retrieves a list value, returning ErrNotFound or a *ConversionError
+func (c *Config) StrSliceE(key string) ([]string, error) {
+	value, ok := c.lookup(key)
+	return typedValueE(key, value, ok, listParser(c.separator()))
+}
+
+// IntSliceE retrieves a list of integers, returning ErrNotFound or a *ConversionError
+func (c *Config) IntSliceE(key string) ([]int, error) {
+	value, ok := c.lookup(key)
+	return typedValueE(key, value, ok, intListParser(c.separator()))
+}
+
+// StringMapE retrieves a map value or the nested keys below key, returning ErrNotFound or a *ConversionError
+func (c *Config) StringMapE(key string) (map[string]string, error) {
+	value, ok := c.lookup(key)
+	if !ok {
+		if nested := c.nestedValues(key); len(nested) > 0 {
+			return nested, nil
+		}
+	}
+	return typedValueE(key, value, ok, mapParser(c.separator()))
+}
+
+// Lookup returns the value of an environment variable and whether it is set
+func Lookup(key string) (string, bool) {
+	return os.LookupEnv(key)
+}
+
+// StrE retrieves a string environment variable, returning ErrNotFound if it is not set
+func StrE(key string) (string, error) {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return "", notFound(key)
+	}
+	return value, nil
+}
+
+// IntE retrieves an integer environment variable, returning ErrNotFound or a *ConversionError
+func IntE(key string) (int, error) {
+	value, ok := os.LookupEnv(key)
+	return typedValueE(key, value, ok, parseInt)
+}
+
+// BoolE retrieves a boolean environment variable, returning ErrNotFound or a *ConversionError
+func BoolE(key string) (bool, error) {
+	value, ok := os.LookupEnv(key)
+	return typedValueE(key, value, ok, parseBool)
+}
+
+// Float64E retrieves a float64 environment variable, returning ErrNotFound or a *ConversionError
+func Float64E(key string) (float64, error) {
+	value, ok := os.LookupEnv(key)
+	return typedValueE(key, value, ok, parseFloat64)
+}
+
+// Int64E retrieves an int64 environment variable, returning ErrNotFound or a *ConversionError
+func Int64E(key string) (int64, error) {
+	value, ok := os.LookupEnv(key)
+	return typedValueE(key, value, ok, parseInt64)
+}
+
+// Uint64E retrieves a uint64 environment variable, returning ErrNotFound or a *ConversionError
+func Uint64E(key string) (uint64, error) {
+	value, ok := os.LookupEnv(key)
+	return typedValueE(key, value, ok, parseUint64)
+}
+
+// DurationE retrieves a time.Duration environment variable, returning ErrNotFound or a *ConversionError
+func DurationE(key string) (time.Duration, error) {
+	value, ok := os.LookupEnv(key)
+	return typedValueE(key, value, ok, parseDuration)
+}
+
+// TimeE retrieves an RFC3339 time.Time environment variable, returning ErrNotFound or a *ConversionError
+func TimeE(key string) (time.Time, error) {
+	value, ok := os.LookupEnv(key)
+	return typedValueE(key, value, ok, timeParser(nil))
+}
+
+// TimeLayoutE retrieves a time.Time environment variable parsed as RFC3339 or with layouts, returning ErrNotFound or a *ConversionError
+func TimeLayoutE(key string, layouts []string) (time.Time, error) {
+	value, ok := os.LookupEnv(key)
+	return typedValueE(key, value, ok, timeParser(layouts))
+}
+
+// StrSliceE retrieves a comma-separated list environment variable, returning ErrNotFound or a *ConversionError
+func StrSliceE(key string) ([]string, error) {
+	value, ok := os.LookupEnv(key)
+	return typedValueE(key, value, ok, listParser(defaultSeparator))
+}
+
+// IntSliceE retrieves a comma-separated list of integers, returning ErrNotFound or a *ConversionError
+func IntSliceE(key string) ([]int, error) {
+	value, ok := os.LookupEnv(key)
+	return typedValueE(key, value, ok, intListParser(defaultSeparator))
+}
+
+// StringMapE retrieves a "key=value,key=value" environment variable, returning ErrNotFound or a *ConversionError
+func StringMapE(key string) (map[string]string, error) {
+	value, ok := os.LookupEnv(key)
+	return typedValueE(key, value, ok, mapParser(defaultSeparator))
+}
@@ -0,0 +1,118 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestLookupAndErrorGetters(t *testing.T) {
+	config := NewWithOptions("",
+		WithIsolated(),
+		WithSources(DefaultsSource(map[string]interface{}{
+			"db_port":  "abc",
+			"db_host":  "",
+			"db_pool":  " 10 ",
+			"debug":    "maybe",
+			"timeout":  "30",
+			"ports":    "80,x",
+			"labels":   "app=web",
+			"started":  "yesterday",
+			"ratio":    "0.5",
+			"nested":   map[string]interface{}{"a": "1"},
+			"negative": "-1",
+		})),
+	)
+
+	if value, ok := config.Lookup("DB_HOST"); !ok || value != "" {
+		t.Errorf("Expected DB_HOST to be set and empty, got %q, %v", value, ok)
+	}
+	if _, ok := config.Lookup("DB_MISSING"); ok {
+		t.Error("Expected DB_MISSING not to be set")
+	}
+
+	if value, err := config.StrE("DB_HOST"); err != nil || value != "" {
+		t.Errorf("Expected an empty DB_HOST without error, got %q, %v", value, err)
+	}
+	if _, err := config.StrE("DB_MISSING"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+	if _, err := config.DurationE("DB_MISSING"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for DurationE, got %v", err)
+	}
+
+	_, err := config.IntE("DB_PORT")
+	var convErr *ConversionError
+	if !errors.As(err, &convErr) {
+		t.Fatalf("Expected *ConversionError, got %v", err)
+	}
+	if convErr.Key != "DB_PORT" || convErr.Value != "abc" || convErr.Type != "int" {
+		t.Errorf("Unexpected conversion error %+v", convErr)
+	}
+	if !errors.Is(err, strconv.ErrSyntax) {
+		t.Errorf("Expected the error to wrap strconv.ErrSyntax, got %v", err)
+	}
+	if err.Error() != `failed to convert DB_PORT="abc" to int: invalid syntax` {
+		t.Errorf("Unexpected error message %q", err.Error())
+	}
+
+	// A blank value is a conversion error, not a missing key
+	if _, err := config.IntE("DB_HOST"); !errors.As(err, &convErr) {
+		t.Errorf("Expected *ConversionError for a blank value, got %v", err)
+	}
+
+	failures := map[string]func() error{
+		"BoolE":      func() error { _, err := config.BoolE("DEBUG"); return err },
+		"Uint64E":    func() error { _, err := config.Uint64E("NEGATIVE"); return err },
+		"TimeE":      func() error { _, err := config.TimeE("STARTED"); return err },
+		"IntSliceE":  func() error { _, err := config.IntSliceE("PORTS"); return err },
+		"StringMapE": func() error { _, err := config.StringMapE("DEBUG"); return err },
+	}
+	for name, fn := range failures {
+		if err := fn(); !errors.As(err, &convErr) {
+			t.Errorf("Expected %s to return *ConversionError, got %v", name, err)
+		}
+	}
+
+	if value, err := config.IntE("DB_POOL"); err != nil || value != 10 {
+		t.Errorf("Expected DB_POOL=10, got %d, %v", value, err)
+	}
+	if value, err := config.DurationE("TIMEOUT"); err != nil || value != 30*time.Second {
+		t.Errorf("Expected TIMEOUT=30s, got %v, %v", value, err)
+	}
+	if value, err := config.Float64E("RATIO"); err != nil || value != 0.5 {
+		t.Errorf("Expected RATIO=0.5, got %v, %v", value, err)
+	}
+	if value, err := config.StrSliceE("PORTS"); err != nil || !reflect.DeepEqual(value, []string{"80", "x"}) {
+		t.Errorf("Expected PORTS=[80 x], got %q, %v", value, err)
+	}
+	if value, err := config.StringMapE("NESTED"); err != nil || !reflect.DeepEqual(value, map[string]string{"a": "1"}) {
+		t.Errorf("Expected nested map, got %v, %v", value, err)
+	}
+}
+
+func TestGlobalErrorGetters(t *testing.T) {
+	os.Setenv("GLOBAL_E_PORT", "abc")
+	os.Setenv("GLOBAL_E_EMPTY", "")
+	os.Setenv("GLOBAL_E_COUNT", "3")
+	defer os.Unsetenv("GLOBAL_E_PORT")
+	defer os.Unsetenv("GLOBAL_E_EMPTY")
+	defer os.Unsetenv("GLOBAL_E_COUNT")
+
+	if value, ok := Lookup("GLOBAL_E_EMPTY"); !ok || value != "" {
+		t.Errorf("Expected GLOBAL_E_EMPTY to be set and empty, got %q, %v", value, ok)
+	}
+	if _, err := StrE("GLOBAL_E_MISSING"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+	var convErr *ConversionError
+	if _, err := IntE("GLOBAL_E_PORT"); !errors.As(err, &convErr) || convErr.Value != "abc" {
+		t.Errorf("Expected *ConversionError for GLOBAL_E_PORT, got %v", err)
+	}
+	if value, err := Int64E("GLOBAL_E_COUNT"); err != nil || value != 3 {
+		t.Errorf("Expected GLOBAL_E_COUNT=3, got %d, %v", value, err)
+	}
+}
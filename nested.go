@@ -0,0 +1,81 @@
+package config
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// nestedTree returns the loaded values below the environment name prefix as a nested map
+// Indexed arrays are restored, so the result is an array when every key below
+// prefix is an index. It returns nil when no value is below prefix.
+func (s *loadState) nestedTree(prefix string) interface{} {
+	tree := s.nestedMap(prefix)
+	if tree == nil {
+		return nil
+	}
+	return restoreArrays(tree)
+}
+
+// nestedMap returns the loaded values below the environment name prefix as a nested map
+// Paths are split on dots, so keys from .env files stay whole. Arrays below
+// the top level are restored. It returns nil when no value is below prefix.
+func (s *loadState) nestedMap(prefix string) map[string]interface{} {
+	paths := make([]string, 0, len(s.config))
+	for path := range s.config {
+		if len(path) > len(prefix) && toEnvKey(path[:len(prefix)]) == prefix {
+			paths = append(paths, path)
+		}
+	}
+	if len(paths) == 0 {
+		return nil
+	}
+	sort.Strings(paths)
+
+	tree := make(map[string]interface{})
+	for _, path := range paths {
+		insertPath(tree, strings.Split(path[len(prefix):], "."), s.typed[toEnvKey(path)])
+	}
+	for key, value := range tree {
+		tree[key] = restoreArrays(value)
+	}
+	return tree
+}
+
+// insertPath stores value at the path of segments below tree
+// A value in the way of a longer path is replaced, as the longer path holds more.
+func insertPath(tree map[string]interface{}, segments []string, value interface{}) {
+	for _, segment := range segments[:len(segments)-1] {
+		next, ok := tree[segment].(map[string]interface{})
+		if !ok {
+			next = make(map[string]interface{})
+			tree[segment] = next
+		}
+		tree = next
+	}
+	last := segments[len(segments)-1]
+	if _, ok := tree[last].(map[string]interface{}); !ok {
+		tree[last] = value
+	}
+}
+
+// restoreArrays turns maps keyed 0 to n-1, as flattenConfig writes arrays, back into arrays
+func restoreArrays(value interface{}) interface{} {
+	m, ok := value.(map[string]interface{})
+	if !ok || len(m) == 0 {
+		return value
+	}
+	for key, item := range m {
+		m[key] = restoreArrays(item)
+	}
+
+	items := make([]interface{}, len(m))
+	for key, item := range m {
+		i, err := strconv.Atoi(key)
+		if err != nil || i < 0 || i >= len(m) || strconv.Itoa(i) != key {
+			return m
+		}
+		items[i] = item
+	}
+	return items
+}
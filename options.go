@@ -0,0 +1,106 @@
+package config
+
+import "time"
+
+// Option configures a Config created with NewWithOptions
+type Option func(*Config)
+
+// WithIsolated keeps loaded values inside the Config instead of writing them
+// to the process environment, so several instances never clobber each other.
+// Values can still be exported explicitly with Export.
+func WithIsolated() Option {
+	return func(c *Config) {
+		c.isolated = true
+	}
+}
+
+// WithEnvFallback makes an isolated Config read keys it did not load from the process environment
+func WithEnvFallback() Option {
+	return func(c *Config) {
+		c.envFallback = true
+	}
+}
+
+// WithSources layers additional sources on top of the config file
+// Sources are applied in order, so later sources override earlier ones:
+//
+//	NewWithOptions("",
+//		WithSources(
+//			DefaultsSource(map[string]interface{}{"port": 8080}),
+//			FileSource("config.yaml"),
+//			FileSource("config.production.yaml"),
+//			FileSource(".env"),
+//			EnvSource("APP_"),
+//		))
+func WithSources(sources ...Source) Option {
+	return func(c *Config) {
+		c.layers = append(c.layers, sources...)
+	}
+}
+
+// WithFormat forces the format of the config file instead of detecting it from
+// the extension, e.g. for a file named "config" or ".env.production"
+// SetFile detects the format of the new file again.
+func WithFormat(format ConfigFormat) Option {
+	return func(c *Config) {
+		c.format = format
+	}
+}
+
+// WithStrict turns malformed .env lines and duplicate keys into a *ParseError instead of skipping them
+func WithStrict() Option {
+	return func(c *Config) {
+		c.strict = true
+	}
+}
+
+// WithSchema checks the config file against schema if it is a JSON, YAML or TOML file
+// The document is checked before it is flattened, so the schema describes the
+// file as written. Layers added with WithSources are not checked, so a partial
+// overlay does not have to satisfy the schema on its own. Violations abort the
+// load or reload with a *ValidationError locating each one by file, JSON
+// pointer and line.
+func WithSchema(schema *Schema) Option {
+	return func(c *Config) {
+		c.schema = schema
+	}
+}
+
+// WithWatchErrorHandler sets the function Watch reports failed reloads and watcher errors to
+func WithWatchErrorHandler(fn func(error)) Option {
+	return func(c *Config) {
+		c.watch.onError = fn
+	}
+}
+
+// WithWatchDebounce sets how long Watch waits for writes to settle before reloading
+// The default is 100ms.
+func WithWatchDebounce(d time.Duration) Option {
+	return func(c *Config) {
+		c.watch.debounce = d
+	}
+}
+
+// WithWatchPolling makes Watch poll the files at the given interval instead of
+// using file system notifications, e.g. for network file systems
+func WithWatchPolling(interval time.Duration) Option {
+	return func(c *Config) {
+		c.watch.poll = interval
+	}
+}
+
+// WithTimeLayouts sets the layouts Time tries, in order, for values that are not RFC3339
+func WithTimeLayouts(layouts ...string) Option {
+	return func(c *Config) {
+		c.timeLayouts = append(c.timeLayouts, layouts...)
+	}
+}
+
+// WithSeparator sets the separator StrSlice, IntSlice and StringMap split values on
+// The default is a comma. Arrays in JSON, YAML and TOML files are always joined
+// with commas, so only change it for lists written by hand.
+func WithSeparator(sep string) Option {
+	return func(c *Config) {
+		c.listSeparator = sep
+	}
+}
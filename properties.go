@@ -0,0 +1,151 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+)
+
+// loadPropertiesConfig loads configuration from Java .properties data
+//
+// It follows java.util.Properties: comments start with # or !, keys end at
+// the first unescaped =, : or whitespace, a trailing backslash continues the
+// line, and \t, \n, \r, \f and \uXXXX escapes are decoded. Dotted keys such as
+// database.host map to DATABASE_HOST like nested JSON/YAML keys.
+func loadPropertiesConfig(data []byte, strict bool) (map[string]interface{}, error) {
+	config := make(map[string]interface{})
+	seen := make(map[string]int)
+
+	lines := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		lineNum := i + 1
+		line := strings.TrimLeft(lines[i], " \t\f")
+
+		// Skip empty lines and comments
+		if line == "" || line[0] == '#' || line[0] == '!' {
+			continue
+		}
+
+		// Join continuation lines
+		for endsWithContinuation(line) && i+1 < len(lines) {
+			i++
+			line = line[:len(line)-1] + strings.TrimLeft(lines[i], " \t\f")
+		}
+		if endsWithContinuation(line) {
+			line = line[:len(line)-1]
+		}
+
+		rawKey, rawValue := splitProperty(line)
+		key, err := unescapeProperty(rawKey)
+		if err != nil {
+			return nil, &ParseError{Line: lineNum, Column: 1, Text: lines[lineNum-1], Reason: err.Error()}
+		}
+		value, err := unescapeProperty(rawValue)
+		if err != nil {
+			return nil, &ParseError{Line: lineNum, Column: len(rawKey) + 1, Text: lines[lineNum-1], Reason: err.Error()}
+		}
+
+		if first, ok := seen[key]; ok && strict {
+			return nil, &ParseError{
+				Line:   lineNum,
+				Column: 1,
+				Text:   lines[lineNum-1],
+				Reason: fmt.Sprintf("duplicate key %s, first defined on line %d", key, first),
+			}
+		}
+		seen[key] = lineNum
+		config[key] = value
+	}
+
+	return config, nil
+}
+
+// endsWithContinuation reports whether line ends with an odd number of backslashes
+func endsWithContinuation(line string) bool {
+	count := 0
+	for i := len(line) - 1; i >= 0 && line[i] == '\\'; i-- {
+		count++
+	}
+	return count%2 == 1
+}
+
+// splitProperty splits a logical line into its raw key and value
+func splitProperty(line string) (string, string) {
+	keyEnd := len(line)
+	for i := 0; i < len(line); i++ {
+		if line[i] == '\\' {
+			i++
+			continue
+		}
+		if strings.IndexByte("=: \t\f", line[i]) >= 0 {
+			keyEnd = i
+			break
+		}
+	}
+
+	rest := strings.TrimLeft(line[keyEnd:], " \t\f")
+	if rest != "" && (rest[0] == '=' || rest[0] == ':') {
+		rest = strings.TrimLeft(rest[1:], " \t\f")
+	}
+	return line[:keyEnd], rest
+}
+
+// unescapeProperty decodes the escape sequences of a .properties key or value
+func unescapeProperty(s string) (string, error) {
+	if !strings.Contains(s, "\\") {
+		return s, nil
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 >= len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 't':
+			b.WriteByte('\t')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 'f':
+			b.WriteByte('\f')
+		case 'u':
+			r, err := parseUnicodeEscape(s, i+1)
+			if err != nil {
+				return "", err
+			}
+			i += 4
+
+			// Combine UTF-16 surrogate pairs written as two escapes
+			if utf16.IsSurrogate(r) && strings.HasPrefix(s[i+1:], "\\u") {
+				if low, err := parseUnicodeEscape(s, i+3); err == nil {
+					if combined := utf16.DecodeRune(r, low); combined != unicode.ReplacementChar {
+						r = combined
+						i += 6
+					}
+				}
+			}
+			b.WriteRune(r)
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String(), nil
+}
+
+// parseUnicodeEscape parses the four hex digits of a \uXXXX escape starting at start
+func parseUnicodeEscape(s string, start int) (rune, error) {
+	if start+4 > len(s) {
+		return 0, fmt.Errorf("malformed \\uXXXX escape")
+	}
+	code, err := strconv.ParseUint(s[start:start+4], 16, 16)
+	if err != nil {
+		return 0, fmt.Errorf("malformed \\uXXXX escape")
+	}
+	return rune(code), nil
+}
@@ -0,0 +1,95 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Decoder decodes the contents of a config file into a map of values
+// Nested maps and arrays of objects are flattened like JSON/YAML, so a decoder
+// may return either nested or already flattened (dotted) keys. Decoders may
+// return a *ParseError to report the line and column of a syntax error.
+type Decoder interface {
+	Decode(data []byte) (map[string]interface{}, error)
+}
+
+// DecoderFunc adapts an ordinary function to the Decoder interface
+type DecoderFunc func(data []byte) (map[string]interface{}, error)
+
+// Decode calls f(data)
+func (f DecoderFunc) Decode(data []byte) (map[string]interface{}, error) {
+	return f(data)
+}
+
+// registry holds the formats added with RegisterFormat
+var registry = struct {
+	sync.RWMutex
+	extensions map[string]ConfigFormat
+	decoders   map[ConfigFormat]Decoder
+	next       ConfigFormat
+}{
+	extensions: make(map[string]ConfigFormat),
+	decoders:   make(map[ConfigFormat]Decoder),
+	next:       FormatProperties + 1,
+}
+
+// RegisterFormat makes files with the given extension load through decoder
+// and returns the new format, which can be passed to WithFormat, FileSourceAs
+// or LoadConfigFileAs to force it for files with another extension:
+//
+//	var FormatHCL = config.RegisterFormat(".hcl", hclDecoder{})
+//
+// Extensions are matched case-insensitively and the leading dot is optional.
+// Registering an extension again, including a built-in one, replaces the
+// previous mapping. RegisterFormat panics if ext is empty or decoder is nil.
+func RegisterFormat(ext string, decoder Decoder) ConfigFormat {
+	ext = strings.ToLower(strings.TrimPrefix(ext, "."))
+	if ext == "" {
+		panic("config: RegisterFormat extension is empty")
+	}
+	if decoder == nil {
+		panic("config: RegisterFormat decoder is nil")
+	}
+
+	registry.Lock()
+	defer registry.Unlock()
+
+	format := registry.next
+	registry.next++
+	registry.extensions["."+ext] = format
+	registry.decoders[format] = decoder
+	return format
+}
+
+// registeredFormat returns the format registered for ext
+func registeredFormat(ext string) (ConfigFormat, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	format, ok := registry.extensions[ext]
+	return format, ok
+}
+
+// registeredDecoder returns the decoder of a format added with RegisterFormat
+func registeredDecoder(format ConfigFormat) (Decoder, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	decoder, ok := registry.decoders[format]
+	return decoder, ok
+}
+
+// decodeRegistered loads the data of filePath with a registered decoder
+func decodeRegistered(decoder Decoder, data []byte, filePath string) (map[string]interface{}, error) {
+	config, err := decoder.Decode(data)
+	if err != nil {
+		var parseErr *ParseError
+		if errors.As(err, &parseErr) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to parse config file %s: %w", filePath, err)
+	}
+	return flattenConfig(config, ""), nil
+}
@@ -0,0 +1,120 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+// kvDecoder decodes "key -> value" lines
+func kvDecoder(data []byte) (map[string]interface{}, error) {
+	config := make(map[string]interface{})
+	for i, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "->")
+		if !ok {
+			return nil, &ParseError{Line: i + 1, Text: line, Reason: "missing '->'"}
+		}
+		config[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	return config, nil
+}
+
+func TestRegisterFormat(t *testing.T) {
+	formatKV := RegisterFormat(".KV", DecoderFunc(kvDecoder))
+
+	if format := detectFormat("settings.kv"); format != formatKV {
+		t.Errorf("Expected settings.kv to use the registered format, got %v", format)
+	}
+
+	err := createTestFile("registry.kv", "registry.host -> kv.local\nregistry.port -> 7000\n")
+	if err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	defer cleanupTestFile("registry.kv")
+
+	config := NewWithOptions("registry.kv", WithIsolated())
+	if value := config.Str("REGISTRY_HOST"); value != "kv.local" {
+		t.Errorf("Expected REGISTRY_HOST=kv.local, got %s", value)
+	}
+	if value := config.Int("REGISTRY_PORT"); value != 7000 {
+		t.Errorf("Expected REGISTRY_PORT=7000, got %d", value)
+	}
+
+	err = createTestFile("registry_bad.kv", "registry.host -> kv.local\nbroken\n")
+	if err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	defer cleanupTestFile("registry_bad.kv")
+
+	config = NewWithOptions("", WithIsolated(), WithSources(FileSource("registry_bad.kv")))
+	var parseErr *ParseError
+	if err := config.Reload(); !errors.As(err, &parseErr) {
+		t.Fatalf("Expected *ParseError, got %v", err)
+	}
+	if parseErr.File != "registry_bad.kv" || parseErr.Line != 2 {
+		t.Errorf("Expected error at registry_bad.kv:2, got %s:%d", parseErr.File, parseErr.Line)
+	}
+}
+
+func TestRegisterFormatDecoderError(t *testing.T) {
+	decodeErr := errors.New("decoder failed")
+	formatFail := RegisterFormat("fail", DecoderFunc(func([]byte) (map[string]interface{}, error) {
+		return nil, decodeErr
+	}))
+
+	err := createTestFile("registry_config", "anything")
+	if err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	defer cleanupTestFile("registry_config")
+
+	_, err = loadConfigFileAs("registry_config", formatFail, loadOptions{})
+	if !errors.Is(err, decodeErr) {
+		t.Fatalf("Expected decoder error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "registry_config") {
+		t.Errorf("Expected error to name the file, got %v", err)
+	}
+}
+
+func TestForcedFormat(t *testing.T) {
+	err := createTestFile("forced_config", "forced:\n  host: yaml.local\n  port: 8443\n")
+	if err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	defer cleanupTestFile("forced_config")
+
+	err = createTestFile("forced.env.production", `{"forced": {"mode": "json"}}`)
+	if err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	defer cleanupTestFile("forced.env.production")
+
+	config := NewWithOptions("forced_config",
+		WithIsolated(),
+		WithFormat(FormatYAML),
+		WithSources(FileSourceAs("forced.env.production", FormatJSON)),
+	)
+	if value := config.Str("FORCED_HOST"); value != "yaml.local" {
+		t.Errorf("Expected FORCED_HOST=yaml.local, got %s", value)
+	}
+	if value := config.Int("FORCED_PORT"); value != 8443 {
+		t.Errorf("Expected FORCED_PORT=8443, got %d", value)
+	}
+	if value := config.Str("FORCED_MODE"); value != "json" {
+		t.Errorf("Expected FORCED_MODE=json, got %s", value)
+	}
+
+	if err := LoadConfigFileAs("forced_config", FormatYAML); err != nil {
+		t.Fatalf("Failed to load config file: %v", err)
+	}
+	defer os.Unsetenv("FORCED_HOST")
+	defer os.Unsetenv("FORCED_PORT")
+	if value := os.Getenv("FORCED_HOST"); value != "yaml.local" {
+		t.Errorf("Expected FORCED_HOST=yaml.local in the environment, got %s", value)
+	}
+}
@@ -0,0 +1,136 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestReloadKeepsStateOnError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "reload.json")
+	if err := os.WriteFile(path, []byte(`{"reload_tx": {"host": "good.local", "port": 5432}}`), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	config := New(path)
+	defer os.Unsetenv("RELOAD_TX_HOST")
+	defer os.Unsetenv("RELOAD_TX_PORT")
+
+	notified := false
+	config.OnChange(func(ChangeSet) { notified = true })
+
+	if err := os.WriteFile(path, []byte(`{"reload_tx": {"host": "bad.local",`), 0644); err != nil {
+		t.Fatalf("Failed to update test file: %v", err)
+	}
+	if err := config.Reload(); err == nil {
+		t.Fatal("Expected Reload to fail on a syntax error")
+	}
+
+	if value := config.Str("RELOAD_TX_HOST"); value != "good.local" {
+		t.Errorf("Expected RELOAD_TX_HOST to keep good.local, got %s", value)
+	}
+	if value := os.Getenv("RELOAD_TX_PORT"); value != "5432" {
+		t.Errorf("Expected exported RELOAD_TX_PORT to keep 5432, got %s", value)
+	}
+	if origin, ok := config.Origin("RELOAD_TX_HOST"); !ok || origin != path {
+		t.Errorf("Expected RELOAD_TX_HOST origin %s, got %s", path, origin)
+	}
+	if notified {
+		t.Error("Expected a failed reload not to notify subscribers")
+	}
+
+	// The next successful reload replaces the values
+	if err := os.WriteFile(path, []byte(`{"reload_tx": {"host": "new.local"}}`), 0644); err != nil {
+		t.Fatalf("Failed to update test file: %v", err)
+	}
+	if err := config.Reload(); err != nil {
+		t.Fatalf("Failed to reload config: %v", err)
+	}
+	if value := config.Str("RELOAD_TX_HOST"); value != "new.local" {
+		t.Errorf("Expected RELOAD_TX_HOST=new.local, got %s", value)
+	}
+	if _, ok := os.LookupEnv("RELOAD_TX_PORT"); ok {
+		t.Error("Expected RELOAD_TX_PORT to be unset once removed from the file")
+	}
+}
+
+func TestSetFileKeepsStateOnError(t *testing.T) {
+	dir := t.TempDir()
+	good := filepath.Join(dir, "good.env")
+	if err := os.WriteFile(good, []byte("SETFILE_TX=good\n"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	bad := filepath.Join(dir, "bad.yaml")
+	if err := os.WriteFile(bad, []byte("setfile_tx: [unclosed\n"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	config := NewWithOptions(good, WithIsolated())
+	if err := config.SetFile(bad); err == nil {
+		t.Fatal("Expected SetFile to fail on a syntax error")
+	}
+	if value := config.Str("SETFILE_TX"); value != "good" {
+		t.Errorf("Expected SETFILE_TX to keep good, got %s", value)
+	}
+
+	// The previous file is still the one reloaded
+	if err := os.WriteFile(good, []byte("SETFILE_TX=updated\n"), 0644); err != nil {
+		t.Fatalf("Failed to update test file: %v", err)
+	}
+	if err := config.Reload(); err != nil {
+		t.Fatalf("Failed to reload config: %v", err)
+	}
+	if value := config.Str("SETFILE_TX"); value != "updated" {
+		t.Errorf("Expected SETFILE_TX=updated, got %s", value)
+	}
+}
+
+func TestReloadNeverExposesMissingKeys(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "reload.env")
+	if err := os.WriteFile(path, []byte("RELOAD_READER=value\n"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	config := New(path)
+	defer os.Unsetenv("RELOAD_READER")
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	missing := make(chan string, 1)
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				if value := config.Str("RELOAD_READER"); value != "value" {
+					select {
+					case missing <- value:
+					default:
+					}
+					return
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < 200; i++ {
+		if err := config.Reload(); err != nil {
+			t.Fatalf("Failed to reload config: %v", err)
+		}
+	}
+	close(stop)
+	wg.Wait()
+
+	select {
+	case value := <-missing:
+		t.Errorf("Expected readers to always see RELOAD_READER=value, got %q", value)
+	default:
+	}
+}
@@ -0,0 +1,225 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Rule checks a configuration value, see Config.AddRule
+// Check returns an error describing why the value is invalid, e.g. "must be at
+// least 1", which becomes the message of the reported Violation.
+type Rule struct {
+	Name  string // Rule name reported in Violation.Rule, e.g. "min"
+	Check func(value string) error
+
+	checkEmpty bool // Also check keys set to an empty value
+}
+
+// ruleError is a failed Rule, reported by Unmarshal as the error of a FieldError
+type ruleError struct {
+	rule string
+	err  error
+}
+
+func (e *ruleError) Error() string {
+	return e.err.Error()
+}
+
+func (e *ruleError) Unwrap() error {
+	return e.err
+}
+
+// checkRules returns the rules broken by a value that is set
+// Empty values count as unset, like in the getters, so only NonEmpty checks them.
+func checkRules(value string, rules []Rule) []*ruleError {
+	var failed []*ruleError
+	for _, rule := range rules {
+		if value == "" && !rule.checkEmpty {
+			continue
+		}
+		if err := rule.Check(value); err != nil {
+			failed = append(failed, &ruleError{rule: rule.Name, err: err})
+		}
+	}
+	return failed
+}
+
+// Min requires a number greater than or equal to n
+func Min(n float64) Rule {
+	return Rule{Name: "min", Check: func(value string) error {
+		f, err := parseFloat64(value)
+		if err != nil {
+			return errors.New("must be a number")
+		}
+		if f < n {
+			return fmt.Errorf("must be at least %s", formatNumber(n))
+		}
+		return nil
+	}}
+}
+
+// Max requires a number less than or equal to n
+func Max(n float64) Rule {
+	return Rule{Name: "max", Check: func(value string) error {
+		f, err := parseFloat64(value)
+		if err != nil {
+			return errors.New("must be a number")
+		}
+		if f > n {
+			return fmt.Errorf("must be at most %s", formatNumber(n))
+		}
+		return nil
+	}}
+}
+
+// OneOf requires one of the given values, ignoring surrounding whitespace
+func OneOf(values ...string) Rule {
+	return Rule{Name: "oneof", Check: func(value string) error {
+		for _, allowed := range values {
+			if strings.TrimSpace(value) == allowed {
+				return nil
+			}
+		}
+		return fmt.Errorf("must be one of %s", strings.Join(values, ", "))
+	}}
+}
+
+// Regex requires a value matching pattern and panics if pattern does not compile
+func Regex(pattern string) Rule {
+	return regexRule(regexp.MustCompile(pattern))
+}
+
+func regexRule(re *regexp.Regexp) Rule {
+	return Rule{Name: "regex", Check: func(value string) error {
+		if !re.MatchString(value) {
+			return fmt.Errorf("must match %s", re)
+		}
+		return nil
+	}}
+}
+
+// URL requires an absolute URL with a scheme and host, e.g. https://example.com
+func URL() Rule {
+	return Rule{Name: "url", Check: func(value string) error {
+		u, err := url.Parse(strings.TrimSpace(value))
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return errors.New("must be an absolute URL")
+		}
+		return nil
+	}}
+}
+
+// HostPort requires a "host:port" address, the host may be empty as in ":8080"
+func HostPort() Rule {
+	return Rule{Name: "hostport", Check: func(value string) error {
+		_, port, err := net.SplitHostPort(strings.TrimSpace(value))
+		if err != nil || !isPort(port) {
+			return errors.New("must be in host:port form")
+		}
+		return nil
+	}}
+}
+
+// Email requires a bare email address such as user@example.com
+func Email() Rule {
+	return Rule{Name: "email", Check: func(value string) error {
+		value = strings.TrimSpace(value)
+		addr, err := mail.ParseAddress(value)
+		if err != nil || addr.Address != value {
+			return errors.New("must be an email address")
+		}
+		return nil
+	}}
+}
+
+// Port requires a port number between 1 and 65535
+func Port() Rule {
+	return Rule{Name: "port", Check: func(value string) error {
+		if !isPort(strings.TrimSpace(value)) {
+			return errors.New("must be a port number between 1 and 65535")
+		}
+		return nil
+	}}
+}
+
+// NonEmpty rejects keys that are set to an empty or blank value
+// Missing keys are left to Require.
+func NonEmpty() Rule {
+	return Rule{Name: "nonempty", checkEmpty: true, Check: func(value string) error {
+		if strings.TrimSpace(value) == "" {
+			return errors.New("must not be empty")
+		}
+		return nil
+	}}
+}
+
+// isPort reports whether s is a decimal port number between 1 and 65535
+func isPort(s string) bool {
+	n, err := strconv.ParseUint(s, 10, 16)
+	return err == nil && n > 0
+}
+
+// formatNumber formats a rule bound without trailing zeros
+func formatNumber(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
+
+// parseRules parses a `validate:"..."` struct tag such as "min=1,max=65535"
+// oneof takes space-separated values and regex takes the rest of the tag, so it
+// must come last if its pattern contains a comma.
+func parseRules(tag string) ([]Rule, error) {
+	var rules []Rule
+	for tag = strings.TrimSpace(tag); tag != ""; tag = strings.TrimSpace(tag) {
+		var item string
+		if strings.HasPrefix(tag, "regex=") {
+			item, tag = tag, ""
+		} else {
+			item, tag, _ = strings.Cut(tag, ",")
+		}
+
+		name, arg, hasArg := strings.Cut(strings.TrimSpace(item), "=")
+		if needsArg := name == "min" || name == "max" || name == "oneof" || name == "regex"; needsArg != hasArg {
+			return nil, fmt.Errorf("invalid rule %q", item)
+		}
+
+		switch name {
+		case "min", "max":
+			n, err := strconv.ParseFloat(strings.TrimSpace(arg), 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s bound %q", name, arg)
+			}
+			if name == "min" {
+				rules = append(rules, Min(n))
+			} else {
+				rules = append(rules, Max(n))
+			}
+		case "oneof":
+			rules = append(rules, OneOf(strings.Fields(arg)...))
+		case "regex":
+			re, err := regexp.Compile(arg)
+			if err != nil {
+				return nil, fmt.Errorf("invalid regex: %w", err)
+			}
+			rules = append(rules, regexRule(re))
+		case "url":
+			rules = append(rules, URL())
+		case "hostport":
+			rules = append(rules, HostPort())
+		case "email":
+			rules = append(rules, Email())
+		case "port":
+			rules = append(rules, Port())
+		case "nonempty":
+			rules = append(rules, NonEmpty())
+		default:
+			return nil, fmt.Errorf("unknown rule %q", name)
+		}
+	}
+	return rules, nil
+}
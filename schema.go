@@ -0,0 +1,777 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Schema is a compiled JSON Schema, see CompileSchema
+type Schema struct {
+	root *schemaNode
+}
+
+// schemaNode is a compiled schema or subschema
+type schemaNode struct {
+	always *bool // Set for the boolean schemas true and false
+
+	types    []string
+	enum     []interface{}
+	constant interface{}
+	hasConst bool
+
+	properties        map[string]*schemaNode
+	patternProperties []patternSchema
+	additional        *schemaNode
+	required          []string
+	minProperties     *int
+	maxProperties     *int
+
+	prefixItems []*schemaNode
+	items       *schemaNode
+	minItems    *int
+	maxItems    *int
+	uniqueItems bool
+
+	minimum          *float64
+	maximum          *float64
+	exclusiveMinimum *float64
+	exclusiveMaximum *float64
+	multipleOf       *float64
+
+	minLength *int
+	maxLength *int
+	pattern   *regexp.Regexp
+
+	allOf []*schemaNode
+	anyOf []*schemaNode
+	oneOf []*schemaNode
+	not   *schemaNode
+	ref   *schemaNode
+}
+
+// patternSchema applies a schema to the properties matching a pattern
+type patternSchema struct {
+	pattern *regexp.Regexp
+	schema  *schemaNode
+}
+
+// CompileSchema compiles a JSON Schema document
+//
+// A subset of draft 2020-12 is supported: type, enum, const, properties,
+// patternProperties, additionalProperties, required, min/maxProperties,
+// prefixItems, items, min/maxItems, uniqueItems, minimum, maximum,
+// exclusiveMinimum, exclusiveMaximum, multipleOf, min/maxLength, pattern,
+// allOf, anyOf, oneOf, not and $ref to "#" or a JSON pointer within the
+// document such as "#/$defs/port". Other keywords, including format, are
+// ignored. Patterns use Go regexp syntax.
+func CompileSchema(data []byte) (*Schema, error) {
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse schema: %w", err)
+	}
+	c := &schemaCompiler{doc: doc, nodes: make(map[string]*schemaNode)}
+	root, err := c.compile(doc, "")
+	if err != nil {
+		return nil, err
+	}
+	return &Schema{root: root}, nil
+}
+
+// MustCompileSchema is like CompileSchema but panics if the schema is invalid
+func MustCompileSchema(data []byte) *Schema {
+	schema, err := CompileSchema(data)
+	if err != nil {
+		panic(err)
+	}
+	return schema
+}
+
+// schemaCompiler compiles a schema document, sharing nodes between $ref targets
+type schemaCompiler struct {
+	doc   interface{}
+	nodes map[string]*schemaNode // Compiled nodes by JSON pointer
+}
+
+// compile compiles the subschema raw found at ptr
+func (c *schemaCompiler) compile(raw interface{}, ptr string) (*schemaNode, error) {
+	if node, ok := c.nodes[ptr]; ok {
+		return node, nil
+	}
+	node := &schemaNode{}
+	c.nodes[ptr] = node // Registered first so recursive references terminate
+
+	if b, ok := raw.(bool); ok {
+		node.always = &b
+		return node, nil
+	}
+	obj, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid schema at %q: must be an object or a boolean", ptr)
+	}
+
+	var err error
+	fail := func(keyword string, e error) error {
+		return fmt.Errorf("invalid schema at %q: %w", ptr+"/"+keyword, e)
+	}
+	sub := func(keyword string) (*schemaNode, error) {
+		raw, ok := obj[keyword]
+		if !ok {
+			return nil, nil
+		}
+		return c.compile(raw, ptr+"/"+escapePointer(keyword))
+	}
+	subList := func(keyword string) ([]*schemaNode, error) {
+		raw, ok := obj[keyword]
+		if !ok {
+			return nil, nil
+		}
+		list, ok := raw.([]interface{})
+		if !ok {
+			return nil, fail(keyword, errNotArray)
+		}
+		nodes := make([]*schemaNode, len(list))
+		for i, item := range list {
+			if nodes[i], err = c.compile(item, fmt.Sprintf("%s/%s/%d", ptr, keyword, i)); err != nil {
+				return nil, err
+			}
+		}
+		return nodes, nil
+	}
+
+	switch t := obj["type"].(type) {
+	case nil:
+	case string:
+		node.types = []string{t}
+	case []interface{}:
+		for _, item := range t {
+			name, ok := item.(string)
+			if !ok {
+				return nil, fail("type", errNotString)
+			}
+			node.types = append(node.types, name)
+		}
+	default:
+		return nil, fail("type", errNotString)
+	}
+
+	if raw, ok := obj["enum"]; ok {
+		if node.enum, ok = raw.([]interface{}); !ok {
+			return nil, fail("enum", errNotArray)
+		}
+	}
+	node.constant, node.hasConst = obj["const"]
+
+	if raw, ok := obj["properties"]; ok {
+		props, ok := raw.(map[string]interface{})
+		if !ok {
+			return nil, fail("properties", errNotObject)
+		}
+		node.properties = make(map[string]*schemaNode, len(props))
+		for name, raw := range props {
+			if node.properties[name], err = c.compile(raw, ptr+"/properties/"+escapePointer(name)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if raw, ok := obj["patternProperties"]; ok {
+		props, ok := raw.(map[string]interface{})
+		if !ok {
+			return nil, fail("patternProperties", errNotObject)
+		}
+		for _, pattern := range sortedKeys(props) {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, fail("patternProperties", err)
+			}
+			schema, err := c.compile(props[pattern], ptr+"/patternProperties/"+escapePointer(pattern))
+			if err != nil {
+				return nil, err
+			}
+			node.patternProperties = append(node.patternProperties, patternSchema{pattern: re, schema: schema})
+		}
+	}
+	if node.additional, err = sub("additionalProperties"); err != nil {
+		return nil, err
+	}
+	if raw, ok := obj["required"]; ok {
+		list, ok := raw.([]interface{})
+		if !ok {
+			return nil, fail("required", errNotArray)
+		}
+		for _, item := range list {
+			name, ok := item.(string)
+			if !ok {
+				return nil, fail("required", errNotString)
+			}
+			node.required = append(node.required, name)
+		}
+	}
+
+	if node.prefixItems, err = subList("prefixItems"); err != nil {
+		return nil, err
+	}
+	if node.items, err = sub("items"); err != nil {
+		return nil, err
+	}
+	node.uniqueItems, _ = obj["uniqueItems"].(bool)
+
+	counts := map[string]**int{
+		"minProperties": &node.minProperties,
+		"maxProperties": &node.maxProperties,
+		"minItems":      &node.minItems,
+		"maxItems":      &node.maxItems,
+		"minLength":     &node.minLength,
+		"maxLength":     &node.maxLength,
+	}
+	for keyword, target := range counts {
+		if raw, ok := obj[keyword]; ok {
+			n, ok := raw.(float64)
+			if !ok || n < 0 || n != math.Trunc(n) {
+				return nil, fail(keyword, errNotCount)
+			}
+			count := int(n)
+			*target = &count
+		}
+	}
+
+	bounds := map[string]**float64{
+		"minimum":          &node.minimum,
+		"maximum":          &node.maximum,
+		"exclusiveMinimum": &node.exclusiveMinimum,
+		"exclusiveMaximum": &node.exclusiveMaximum,
+		"multipleOf":       &node.multipleOf,
+	}
+	for keyword, target := range bounds {
+		if raw, ok := obj[keyword]; ok {
+			n, ok := raw.(float64)
+			if !ok || (keyword == "multipleOf" && n <= 0) {
+				return nil, fail(keyword, errNotNumber)
+			}
+			*target = &n
+		}
+	}
+
+	if raw, ok := obj["pattern"]; ok {
+		pattern, ok := raw.(string)
+		if !ok {
+			return nil, fail("pattern", errNotString)
+		}
+		if node.pattern, err = regexp.Compile(pattern); err != nil {
+			return nil, fail("pattern", err)
+		}
+	}
+
+	if node.allOf, err = subList("allOf"); err != nil {
+		return nil, err
+	}
+	if node.anyOf, err = subList("anyOf"); err != nil {
+		return nil, err
+	}
+	if node.oneOf, err = subList("oneOf"); err != nil {
+		return nil, err
+	}
+	if node.not, err = sub("not"); err != nil {
+		return nil, err
+	}
+
+	if raw, ok := obj["$ref"]; ok {
+		ref, ok := raw.(string)
+		if !ok {
+			return nil, fail("$ref", errNotString)
+		}
+		if node.ref, err = c.resolve(ref); err != nil {
+			return nil, fail("$ref", err)
+		}
+	}
+	return node, nil
+}
+
+// resolve compiles the target of a local reference such as "#/$defs/port"
+func (c *schemaCompiler) resolve(ref string) (*schemaNode, error) {
+	ptr, ok := strings.CutPrefix(ref, "#")
+	if !ok || (ptr != "" && !strings.HasPrefix(ptr, "/")) {
+		return nil, fmt.Errorf("unsupported reference %q, only local JSON pointers are supported", ref)
+	}
+
+	target := c.doc
+	for _, token := range splitPointer(ptr) {
+		switch t := target.(type) {
+		case map[string]interface{}:
+			target, ok = t[token]
+		case []interface{}:
+			i, err := strconv.Atoi(token)
+			ok = err == nil && i >= 0 && i < len(t)
+			if ok {
+				target = t[i]
+			}
+		default:
+			ok = false
+		}
+		if !ok {
+			return nil, fmt.Errorf("reference %q not found", ref)
+		}
+	}
+	return c.compile(target, ptr)
+}
+
+var (
+	errNotArray  = fmt.Errorf("must be an array")
+	errNotObject = fmt.Errorf("must be an object")
+	errNotString = fmt.Errorf("must be a string")
+	errNotNumber = fmt.Errorf("must be a valid number")
+	errNotCount  = fmt.Errorf("must be a non-negative integer")
+)
+
+// schemaError is a single failed keyword, located by the JSON pointer of the value
+type schemaError struct {
+	ptr     string
+	keyword string
+	value   interface{}
+	message string
+}
+
+// validate checks a decoded document, returning the failed keywords
+func (s *Schema) validate(doc interface{}) []schemaError {
+	var errs []schemaError
+	s.root.validate(doc, "", &errs)
+	return errs
+}
+
+// validate appends every keyword of n that v fails to errs
+func (n *schemaNode) validate(v interface{}, ptr string, errs *[]schemaError) {
+	report := func(keyword, format string, args ...interface{}) {
+		*errs = append(*errs, schemaError{ptr: ptr, keyword: keyword, value: v, message: fmt.Sprintf(format, args...)})
+	}
+
+	if n.always != nil {
+		if !*n.always {
+			report("false", "is not allowed")
+		}
+		return
+	}
+	if n.ref != nil {
+		n.ref.validate(v, ptr, errs)
+	}
+
+	if len(n.types) > 0 && !hasSchemaType(v, n.types) {
+		report("type", "must be %s", strings.Join(n.types, " or "))
+		return // The remaining keywords would only repeat the mismatch
+	}
+	if n.enum != nil && !containsJSON(n.enum, v) {
+		values := make([]string, len(n.enum))
+		for i, item := range n.enum {
+			values[i] = formatJSON(item)
+		}
+		report("enum", "must be one of %s", strings.Join(values, ", "))
+	}
+	if n.hasConst && !equalJSON(n.constant, v) {
+		report("const", "must be %s", formatJSON(n.constant))
+	}
+
+	switch value := v.(type) {
+	case map[string]interface{}:
+		n.validateObject(value, ptr, errs)
+	case []interface{}:
+		n.validateArray(value, ptr, errs)
+	case string:
+		length := utf8.RuneCountInString(value)
+		if n.minLength != nil && length < *n.minLength {
+			report("minLength", "must be at least %d characters", *n.minLength)
+		}
+		if n.maxLength != nil && length > *n.maxLength {
+			report("maxLength", "must be at most %d characters", *n.maxLength)
+		}
+		if n.pattern != nil && !n.pattern.MatchString(value) {
+			report("pattern", "must match %s", n.pattern)
+		}
+	default:
+		if f, ok := jsonNumber(v); ok {
+			if n.minimum != nil && f < *n.minimum {
+				report("minimum", "must be at least %s", formatNumber(*n.minimum))
+			}
+			if n.maximum != nil && f > *n.maximum {
+				report("maximum", "must be at most %s", formatNumber(*n.maximum))
+			}
+			if n.exclusiveMinimum != nil && f <= *n.exclusiveMinimum {
+				report("exclusiveMinimum", "must be greater than %s", formatNumber(*n.exclusiveMinimum))
+			}
+			if n.exclusiveMaximum != nil && f >= *n.exclusiveMaximum {
+				report("exclusiveMaximum", "must be less than %s", formatNumber(*n.exclusiveMaximum))
+			}
+			if n.multipleOf != nil {
+				if q := f / *n.multipleOf; math.Abs(q-math.Round(q)) > 1e-9 {
+					report("multipleOf", "must be a multiple of %s", formatNumber(*n.multipleOf))
+				}
+			}
+		}
+	}
+
+	for _, sub := range n.allOf {
+		sub.validate(v, ptr, errs)
+	}
+	if len(n.anyOf) > 0 && countValid(n.anyOf, v, ptr) == 0 {
+		report("anyOf", "must match at least one schema in anyOf")
+	}
+	if len(n.oneOf) > 0 {
+		if matched := countValid(n.oneOf, v, ptr); matched != 1 {
+			report("oneOf", "must match exactly one schema in oneOf, matched %d", matched)
+		}
+	}
+	if n.not != nil && countValid([]*schemaNode{n.not}, v, ptr) == 1 {
+		report("not", "must not match the schema in not")
+	}
+}
+
+// validateObject checks the object keywords, visiting properties in sorted order
+func (n *schemaNode) validateObject(obj map[string]interface{}, ptr string, errs *[]schemaError) {
+	for _, name := range n.required {
+		if _, ok := obj[name]; !ok {
+			*errs = append(*errs, schemaError{ptr: ptr + "/" + escapePointer(name), keyword: "required", message: "is required"})
+		}
+	}
+	if n.minProperties != nil && len(obj) < *n.minProperties {
+		*errs = append(*errs, schemaError{ptr: ptr, keyword: "minProperties", value: obj, message: fmt.Sprintf("must have at least %d properties", *n.minProperties)})
+	}
+	if n.maxProperties != nil && len(obj) > *n.maxProperties {
+		*errs = append(*errs, schemaError{ptr: ptr, keyword: "maxProperties", value: obj, message: fmt.Sprintf("must have at most %d properties", *n.maxProperties)})
+	}
+
+	for _, name := range sortedKeys(obj) {
+		value, child := obj[name], ptr+"/"+escapePointer(name)
+		matched := false
+		if sub, ok := n.properties[name]; ok {
+			sub.validate(value, child, errs)
+			matched = true
+		}
+		for _, pp := range n.patternProperties {
+			if pp.pattern.MatchString(name) {
+				pp.schema.validate(value, child, errs)
+				matched = true
+			}
+		}
+		if !matched && n.additional != nil {
+			if n.additional.always != nil && !*n.additional.always {
+				*errs = append(*errs, schemaError{ptr: child, keyword: "additionalProperties", value: value, message: "is not allowed"})
+				continue
+			}
+			n.additional.validate(value, child, errs)
+		}
+	}
+}
+
+// validateArray checks the array keywords
+func (n *schemaNode) validateArray(arr []interface{}, ptr string, errs *[]schemaError) {
+	if n.minItems != nil && len(arr) < *n.minItems {
+		*errs = append(*errs, schemaError{ptr: ptr, keyword: "minItems", value: arr, message: fmt.Sprintf("must have at least %d items", *n.minItems)})
+	}
+	if n.maxItems != nil && len(arr) > *n.maxItems {
+		*errs = append(*errs, schemaError{ptr: ptr, keyword: "maxItems", value: arr, message: fmt.Sprintf("must have at most %d items", *n.maxItems)})
+	}
+	if n.uniqueItems {
+		for i := 1; i < len(arr); i++ {
+			if containsJSON(arr[:i], arr[i]) {
+				*errs = append(*errs, schemaError{ptr: ptr, keyword: "uniqueItems", value: arr, message: "must not contain duplicate items"})
+				break
+			}
+		}
+	}
+	for i, item := range arr {
+		child := ptr + "/" + strconv.Itoa(i)
+		if i < len(n.prefixItems) {
+			n.prefixItems[i].validate(item, child, errs)
+		} else if n.items != nil {
+			n.items.validate(item, child, errs)
+		}
+	}
+}
+
+// countValid returns how many of schemas accept v
+func countValid(schemas []*schemaNode, v interface{}, ptr string) int {
+	count := 0
+	for _, sub := range schemas {
+		var errs []schemaError
+		if sub.validate(v, ptr, &errs); len(errs) == 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// hasSchemaType reports whether v is one of the JSON types
+func hasSchemaType(v interface{}, types []string) bool {
+	for _, t := range types {
+		switch t {
+		case "null":
+			if v == nil {
+				return true
+			}
+		case "boolean":
+			if _, ok := v.(bool); ok {
+				return true
+			}
+		case "object":
+			if _, ok := v.(map[string]interface{}); ok {
+				return true
+			}
+		case "array":
+			if _, ok := v.([]interface{}); ok {
+				return true
+			}
+		case "string":
+			switch v.(type) {
+			case string, time.Time:
+				return true
+			}
+		case "number":
+			if _, ok := jsonNumber(v); ok {
+				return true
+			}
+		case "integer":
+			if f, ok := jsonNumber(v); ok && f == math.Trunc(f) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// jsonNumber converts the numeric types produced by the JSON, YAML and TOML decoders
+func jsonNumber(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// equalJSON compares decoded values the way JSON Schema does, so 1 equals 1.0
+func equalJSON(a, b interface{}) bool {
+	if fa, ok := jsonNumber(a); ok {
+		fb, ok := jsonNumber(b)
+		return ok && fa == fb
+	}
+	switch a := a.(type) {
+	case map[string]interface{}:
+		b, ok := b.(map[string]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for key, value := range a {
+			other, ok := b[key]
+			if !ok || !equalJSON(value, other) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		b, ok := b.([]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !equalJSON(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	case time.Time:
+		return formatValue(a) == formatValue(b)
+	}
+	if t, ok := b.(time.Time); ok {
+		return formatValue(a) == formatValue(t)
+	}
+	return a == b
+}
+
+// containsJSON reports whether list holds a value equal to v
+func containsJSON(list []interface{}, v interface{}) bool {
+	for _, item := range list {
+		if equalJSON(item, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// formatJSON formats a schema value for a message
+func formatJSON(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+// escapePointer escapes a JSON pointer reference token
+func escapePointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+// splitPointer splits a JSON pointer into unescaped reference tokens
+func splitPointer(ptr string) []string {
+	if ptr == "" {
+		return nil
+	}
+	tokens := strings.Split(strings.TrimPrefix(ptr, "/"), "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens
+}
+
+// sortedKeys returns the keys of m in sorted order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// schemaViolations converts failed keywords into violations located by line
+// The line of a missing property is the line of the object that lacks it.
+func schemaViolations(errs []schemaError, lines map[string]int) *ValidationError {
+	violations := make([]Violation, len(errs))
+	for i, e := range errs {
+		value := ""
+		switch e.value.(type) {
+		case nil, map[string]interface{}, []interface{}:
+		default:
+			value = formatValue(e.value)
+		}
+		violations[i] = Violation{
+			Key:     pointerKey(e.ptr),
+			Path:    e.ptr,
+			Line:    pointerLine(lines, e.ptr),
+			Value:   value,
+			Rule:    e.keyword,
+			Message: e.message,
+		}
+	}
+	sort.SliceStable(violations, func(i, j int) bool {
+		return violations[i].Line < violations[j].Line
+	})
+	return &ValidationError{Violations: violations}
+}
+
+// pointerKey returns the flattened configuration key of a JSON pointer, e.g. SERVERS_0_HOST
+func pointerKey(ptr string) string {
+	return toEnvKey(strings.Join(splitPointer(ptr), "_"))
+}
+
+// pointerLine returns the line of ptr, or of its closest ancestor present in lines
+func pointerLine(lines map[string]int, ptr string) int {
+	for {
+		if line, ok := lines[ptr]; ok {
+			return line
+		}
+		i := strings.LastIndexByte(ptr, '/')
+		if i < 0 {
+			return 0
+		}
+		ptr = ptr[:i]
+	}
+}
+
+// jsonLines maps the JSON pointer of every value in a JSON document to its line
+// Object members are located by their key.
+func jsonLines(data []byte) map[string]int {
+	lines := make(map[string]int)
+	dec := json.NewDecoder(bytes.NewReader(data))
+	lineAt := func() int {
+		return bytes.Count(data[:dec.InputOffset()], []byte("\n")) + 1
+	}
+
+	var walk func(ptr string) error
+	walk = func(ptr string) error {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		if _, ok := lines[ptr]; !ok {
+			lines[ptr] = lineAt()
+		}
+		delim, ok := tok.(json.Delim)
+		if !ok {
+			return nil
+		}
+		for i := 0; dec.More(); i++ {
+			child := ptr + "/" + strconv.Itoa(i)
+			if delim == '{' {
+				key, err := dec.Token()
+				if err != nil {
+					return err
+				}
+				child = ptr + "/" + escapePointer(key.(string))
+				lines[child] = lineAt()
+			}
+			if err := walk(child); err != nil {
+				return err
+			}
+		}
+		_, err = dec.Token() // Closing delimiter
+		return err
+	}
+	walk("") // The document was already decoded, errors only cut the map short
+	return lines
+}
+
+// yamlLines maps the JSON pointer of every value in a YAML document to its line
+// Mapping entries are located by their key.
+func yamlLines(data []byte) map[string]int {
+	lines := make(map[string]int)
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return lines
+	}
+
+	var walk func(node *yaml.Node, ptr string)
+	walk = func(node *yaml.Node, ptr string) {
+		if node.Kind == yaml.AliasNode && node.Alias != nil {
+			node = node.Alias
+		}
+		if _, ok := lines[ptr]; !ok {
+			lines[ptr] = node.Line
+		}
+		switch node.Kind {
+		case yaml.DocumentNode:
+			if len(node.Content) > 0 {
+				walk(node.Content[0], ptr)
+			}
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				child := ptr + "/" + escapePointer(node.Content[i].Value)
+				lines[child] = node.Content[i].Line
+				walk(node.Content[i+1], child)
+			}
+		case yaml.SequenceNode:
+			for i, item := range node.Content {
+				walk(item, ptr+"/"+strconv.Itoa(i))
+			}
+		}
+	}
+	walk(&doc, "")
+	return lines
+}
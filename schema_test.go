@@ -0,0 +1,238 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testSchema = `{
+	"$schema": "https://json-schema.org/draft/2020-12/schema",
+	"type": "object",
+	"required": ["server", "log_level"],
+	"additionalProperties": false,
+	"properties": {
+		"log_level": {"enum": ["debug", "info", "warn"]},
+		"server": {
+			"type": "object",
+			"required": ["host", "port"],
+			"properties": {
+				"host": {"type": "string", "minLength": 1},
+				"port": {"$ref": "#/$defs/port"}
+			}
+		},
+		"replicas": {
+			"type": "array",
+			"minItems": 1,
+			"items": {
+				"type": "object",
+				"properties": {"port": {"$ref": "#/$defs/port"}}
+			}
+		},
+		"tags": {"type": "array", "items": {"type": "string", "pattern": "^[a-z]+$"}, "uniqueItems": true}
+	},
+	"$defs": {
+		"port": {"type": "integer", "minimum": 1, "maximum": 65535}
+	}
+}`
+
+func TestSchemaYAML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	content := `server:
+  host: ""
+  port: 70000
+replicas:
+  - port: 8080
+  - port: "8081"
+tags: [web, Web, web]
+debug: true
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	config := NewWithOptions(path, WithIsolated(), WithSchema(MustCompileSchema([]byte(testSchema))))
+	err := config.Load()
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Expected *ValidationError, got %v", err)
+	}
+
+	expected := []Violation{
+		{Key: "LOG_LEVEL", Path: "/log_level", Line: 1, Rule: "required", Message: "is required"},
+		{Key: "SERVER_HOST", Path: "/server/host", Line: 2, Rule: "minLength", Message: "must be at least 1 characters"},
+		{Key: "SERVER_PORT", Path: "/server/port", Line: 3, Value: "70000", Rule: "maximum", Message: "must be at most 65535"},
+		{Key: "REPLICAS_1_PORT", Path: "/replicas/1/port", Line: 6, Value: "8081", Rule: "type", Message: "must be integer"},
+		{Key: "TAGS", Path: "/tags", Line: 7, Rule: "uniqueItems", Message: "must not contain duplicate items"},
+		{Key: "TAGS_1", Path: "/tags/1", Line: 7, Value: "Web", Rule: "pattern", Message: "must match ^[a-z]+$"},
+		{Key: "DEBUG", Path: "/debug", Line: 8, Value: "true", Rule: "additionalProperties", Message: "is not allowed"},
+	}
+	if len(validationErr.Violations) != len(expected) {
+		t.Fatalf("Expected %d violations, got %v", len(expected), err)
+	}
+	for i, v := range validationErr.Violations {
+		expected[i].File = path
+		if v != expected[i] {
+			t.Errorf("Expected violation %+v, got %+v", expected[i], v)
+		}
+	}
+	if msg := validationErr.Violations[2].String(); msg != path+":3: /server/port must be at most 65535" {
+		t.Errorf("Unexpected violation message %q", msg)
+	}
+	if _, ok := config.Lookup("SERVER_PORT"); ok {
+		t.Error("Expected a document failing the schema not to be loaded")
+	}
+}
+
+func TestSchemaJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	content := `{
+	"log_level": "trace",
+	"server": {
+		"host": "localhost",
+		"port": 0
+	}
+}`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	config := NewWithOptions(path, WithIsolated(), WithSchema(MustCompileSchema([]byte(testSchema))))
+	err := config.Load()
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || len(validationErr.Violations) != 2 {
+		t.Fatalf("Expected 2 violations, got %v", err)
+	}
+	if v := validationErr.Violations[0]; v.Path != "/log_level" || v.Line != 2 || v.Rule != "enum" || v.Message != `must be one of "debug", "info", "warn"` {
+		t.Errorf("Unexpected violation %+v", v)
+	}
+	if v := validationErr.Violations[1]; v.Path != "/server/port" || v.Line != 5 || v.Rule != "minimum" {
+		t.Errorf("Unexpected violation %+v", v)
+	}
+}
+
+func TestSchemaKeywords(t *testing.T) {
+	schema := MustCompileSchema([]byte(`{
+		"type": "object",
+		"properties": {
+			"mode": {"oneOf": [{"const": "auto"}, {"type": "integer", "multipleOf": 5}]},
+			"ratio": {"type": "number", "exclusiveMinimum": 0, "exclusiveMaximum": 1},
+			"name": {"not": {"const": "admin"}, "maxLength": 5},
+			"point": {"type": "array", "prefixItems": [{"type": "number"}, {"type": "number"}], "items": false},
+			"node": {"$ref": "#/$defs/node"},
+			"any": {"anyOf": [{"type": "null"}, {"type": "boolean"}]}
+		},
+		"patternProperties": {"^x_": {"type": "string"}},
+		"$defs": {
+			"node": {"type": "object", "properties": {"child": {"$ref": "#/$defs/node"}}, "maxProperties": 1}
+		}
+	}`))
+
+	valid := map[string]interface{}{
+		"mode":  15.0,
+		"ratio": 0.5,
+		"name":  "bob",
+		"point": []interface{}{1, 2.5},
+		"node":  map[string]interface{}{"child": map[string]interface{}{"child": map[string]interface{}{}}},
+		"any":   nil,
+		"x_tag": "a",
+	}
+	if errs := schema.validate(valid); len(errs) != 0 {
+		t.Errorf("Expected a valid document, got %+v", errs)
+	}
+
+	invalid := map[string]interface{}{
+		"mode":  7,
+		"ratio": 1,
+		"name":  "admin",
+		"point": []interface{}{1, 2, 3},
+		"node":  map[string]interface{}{"child": map[string]interface{}{"child": 1, "other": 2}},
+		"any":   "x",
+		"x_tag": 1,
+	}
+	var rules []string
+	for _, e := range schema.validate(invalid) {
+		rules = append(rules, e.ptr+" "+e.keyword)
+	}
+	expected := []string{
+		"/any anyOf",
+		"/mode oneOf",
+		"/name not",
+		"/node/child maxProperties",
+		"/node/child/child type",
+		"/point/2 false",
+		"/ratio exclusiveMaximum",
+		"/x_tag type",
+	}
+	if strings.Join(rules, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Unexpected violations:\n%s", strings.Join(rules, "\n"))
+	}
+}
+
+func TestCompileSchemaErrors(t *testing.T) {
+	for _, schema := range []string{
+		`{"type": 1}`,
+		`{"pattern": "("}`,
+		`{"minLength": -1}`,
+		`{"properties": {"a": 1}}`,
+		`{"$ref": "#/$defs/missing"}`,
+		`{"$ref": "other.json"}`,
+		`[`,
+	} {
+		if _, err := CompileSchema([]byte(schema)); err == nil {
+			t.Errorf("Expected CompileSchema(%s) to fail", schema)
+		}
+	}
+}
+
+func TestSchemaReloadAndLayers(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	overlay := filepath.Join(dir, "override.yaml")
+	schema := MustCompileSchema([]byte(`{"required": ["name", "port"], "properties": {"port": {"type": "integer"}}}`))
+
+	if err := os.WriteFile(path, []byte("name: app\nport: 80\n"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	if err := os.WriteFile(overlay, []byte("port: 2\n"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	// Only the config file is checked, a partial overlay does not need every required key
+	layered := NewWithOptions(path, WithIsolated(), WithSchema(schema), WithSources(FileSource(overlay)))
+	if err := layered.Load(); err != nil {
+		t.Fatalf("Expected the overlay to load, got %v", err)
+	}
+	if value := layered.Int("PORT"); value != 2 {
+		t.Errorf("Expected PORT=2 from the overlay, got %d", value)
+	}
+
+	// TOML violations have no line
+	tomlPath := filepath.Join(dir, "config.toml")
+	if err := os.WriteFile(tomlPath, []byte("name = \"app\"\nport = \"eighty\"\n"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	var validationErr *ValidationError
+	if err := NewWithOptions(tomlPath, WithIsolated(), WithSchema(schema)).Load(); !errors.As(err, &validationErr) {
+		t.Fatalf("Expected the TOML file to fail, got %v", err)
+	}
+	if v := validationErr.Violations[0]; v.Line != 0 || v.String() != tomlPath+": /port must be integer" {
+		t.Errorf("Unexpected violation %q", v.String())
+	}
+
+	config := NewWithOptions(path, WithIsolated(), WithSchema(schema))
+	if err := config.Load(); err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if err := os.WriteFile(path, []byte("name: app\nport: http\n"), 0644); err != nil {
+		t.Fatalf("Failed to update test file: %v", err)
+	}
+	if err := config.Reload(); !errors.As(err, &validationErr) {
+		t.Fatalf("Expected Reload to fail on the schema, got %v", err)
+	}
+	if value := config.Int("PORT"); value != 80 {
+		t.Errorf("Expected PORT to keep 80, got %d", value)
+	}
+}
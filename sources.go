@@ -0,0 +1,99 @@
+package config
+
+import "strings"
+
+// Source is a single configuration layer
+type Source interface {
+	// Name identifies the layer when reporting which source provided a value
+	Name() string
+	// Load returns the layer's values keyed by flattened config key
+	Load() (map[string]interface{}, error)
+}
+
+// optionSource is implemented by sources whose loading depends on Config options
+type optionSource interface {
+	loadWith(opts loadOptions) (map[string]interface{}, error)
+}
+
+// loadOptions are the Config options applied while loading files
+type loadOptions struct {
+	strict bool    // Reject malformed lines and duplicate keys
+	schema *Schema // Schema checked against JSON, YAML and TOML documents
+}
+
+// FileSource returns a layer that loads a config file of any supported format
+// A missing file yields an empty layer
+func FileSource(path string) Source {
+	return &fileSource{path: path, format: detectFormat(path)}
+}
+
+// FileSourceAs returns a layer that loads a config file in the given format
+// regardless of its extension, e.g. a file named "config" or ".env.production"
+func FileSourceAs(path string, format ConfigFormat) Source {
+	return &fileSource{path: path, format: format}
+}
+
+// EnvSource returns a layer with the environment variables whose name starts with prefix
+// An empty prefix includes the whole process environment
+func EnvSource(prefix string) Source {
+	return &envSource{prefix: prefix}
+}
+
+// DefaultsSource returns a layer with fixed values, nested maps are flattened like JSON/YAML
+func DefaultsSource(values map[string]interface{}) Source {
+	return &defaultsSource{values: values}
+}
+
+// fileSource loads a config file
+type fileSource struct {
+	path   string
+	format ConfigFormat
+}
+
+func (s *fileSource) Name() string {
+	return s.path
+}
+
+func (s *fileSource) Load() (map[string]interface{}, error) {
+	config, err := loadConfigFileAs(s.path, s.format, loadOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return plainValues(config), nil
+}
+
+func (s *fileSource) loadWith(opts loadOptions) (map[string]interface{}, error) {
+	return loadConfigFileAs(s.path, s.format, opts)
+}
+
+// envSource reads the process environment
+type envSource struct {
+	prefix string
+}
+
+func (s *envSource) Name() string {
+	return "env"
+}
+
+func (s *envSource) Load() (map[string]interface{}, error) {
+	config := make(map[string]interface{})
+	for key, value := range All() {
+		if strings.HasPrefix(key, s.prefix) {
+			config[key] = value
+		}
+	}
+	return config, nil
+}
+
+// defaultsSource provides fixed values
+type defaultsSource struct {
+	values map[string]interface{}
+}
+
+func (s *defaultsSource) Name() string {
+	return "defaults"
+}
+
+func (s *defaultsSource) Load() (map[string]interface{}, error) {
+	return flattenConfig(s.values, ""), nil
+}
@@ -0,0 +1,162 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLayeredSources(t *testing.T) {
+	baseContent := `server:
+  host: base.local
+  port: 8080
+  timeout: 30
+layers_db:
+  host: db.base
+`
+	err := createTestFile("layers_base.yaml", baseContent)
+	if err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	defer cleanupTestFile("layers_base.yaml")
+
+	overlayContent := `{"server": {"port": 9090}}`
+	err = createTestFile("layers_overlay.json", overlayContent)
+	if err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	defer cleanupTestFile("layers_overlay.json")
+
+	err = createTestFile("layers_secrets.env", "LAYERS_DB_HOST=db.local\nLAYERS_DB_PASSWORD=secret\n")
+	if err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	defer cleanupTestFile("layers_secrets.env")
+
+	os.Setenv("LAYERS_DB_PASSWORD", "from-env")
+	defer os.Unsetenv("LAYERS_DB_PASSWORD")
+
+	config := NewWithOptions("",
+		WithIsolated(),
+		WithSources(
+			DefaultsSource(map[string]interface{}{
+				"server": map[string]interface{}{"timeout": 10, "tls": false},
+			}),
+			FileSource("layers_base.yaml"),
+			FileSource("layers_overlay.json"),
+			FileSource("layers_secrets.env"),
+			EnvSource("LAYERS_DB_"),
+		))
+
+	tests := []struct {
+		key    string
+		value  string
+		origin string
+	}{
+		{"SERVER_TLS", "false", "defaults"},
+		{"SERVER_TIMEOUT", "30", "layers_base.yaml"},
+		{"SERVER_HOST", "base.local", "layers_base.yaml"},
+		{"SERVER_PORT", "9090", "layers_overlay.json"},
+		{"LAYERS_DB_HOST", "db.local", "layers_secrets.env"},
+		{"LAYERS_DB_PASSWORD", "from-env", "env"},
+	}
+
+	for _, test := range tests {
+		if value := config.Str(test.key); value != test.value {
+			t.Errorf("Expected %s=%s, got %s", test.key, test.value, value)
+		}
+		if origin, _ := config.Origin(test.key); origin != test.origin {
+			t.Errorf("Expected %s to come from %s, got %s", test.key, test.origin, origin)
+		}
+	}
+
+	// Reload re-reads every layer
+	err = createTestFile("layers_overlay.json", `{"server": {"port": 9191}}`)
+	if err != nil {
+		t.Fatalf("Failed to update test file: %v", err)
+	}
+	if err := config.Reload(); err != nil {
+		t.Fatalf("Failed to reload config: %v", err)
+	}
+	if config.Int("SERVER_PORT") != 9191 {
+		t.Errorf("Expected SERVER_PORT=9191 after reload, got %d", config.Int("SERVER_PORT"))
+	}
+}
+
+func TestReloadRestoresEnvironment(t *testing.T) {
+	os.Setenv("RESTORE_TEST", "original")
+	defer os.Unsetenv("RESTORE_TEST")
+
+	err := createTestFile("restore_test.env", "RESTORE_TEST=from-file\n")
+	if err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	defer cleanupTestFile("restore_test.env")
+
+	config := New("restore_test.env")
+	if os.Getenv("RESTORE_TEST") != "from-file" {
+		t.Errorf("Expected RESTORE_TEST=from-file, got %s", os.Getenv("RESTORE_TEST"))
+	}
+
+	// Removing the key from the file restores the value it replaced
+	err = createTestFile("restore_test.env", "")
+	if err != nil {
+		t.Fatalf("Failed to update test file: %v", err)
+	}
+	if err := config.Reload(); err != nil {
+		t.Fatalf("Failed to reload config: %v", err)
+	}
+	if os.Getenv("RESTORE_TEST") != "original" {
+		t.Errorf("Expected RESTORE_TEST=original after reload, got %s", os.Getenv("RESTORE_TEST"))
+	}
+}
+
+func TestExportedKeySpelling(t *testing.T) {
+	dir := t.TempDir()
+	envPath := filepath.Join(dir, "spelling.env")
+	iniPath := filepath.Join(dir, "spelling.ini")
+	jsonPath := filepath.Join(dir, "spelling.json")
+	files := map[string]string{
+		envPath:  "spelling_db_host=x\nspelling.app.name=y\n",
+		iniPath:  "[spelling_ini]\nhost = z\n",
+		jsonPath: `{"spelling_json": {"host": "w"}}`,
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+	}
+	names := []string{"spelling_db_host", "spelling.app.name", "SPELLING_INI_HOST", "SPELLING_JSON_HOST"}
+	for _, name := range append(names, "SPELLING_DB_HOST", "SPELLING_APP_NAME", "spelling_ini.host") {
+		defer os.Unsetenv(name)
+	}
+
+	config := NewWithOptions(envPath, WithSources(FileSource(iniPath), FileSource(jsonPath)))
+
+	// .env keys are exported as written, the others by environment name
+	for _, name := range names {
+		if _, ok := os.LookupEnv(name); !ok {
+			t.Errorf("Expected %s to be exported", name)
+		}
+	}
+	for _, name := range []string{"SPELLING_DB_HOST", "SPELLING_APP_NAME", "spelling_ini.host"} {
+		if _, ok := os.LookupEnv(name); ok {
+			t.Errorf("Expected %s not to be exported", name)
+		}
+	}
+	if Str("spelling_db_host") != "x" {
+		t.Errorf("Expected the global getter to read spelling_db_host, got %q", Str("spelling_db_host"))
+	}
+
+	// Instance getters accept either spelling
+	if config.Str("SPELLING_DB_HOST") != "x" || config.Str("spelling.app.name") != "y" {
+		t.Error("Expected instance getters to read keys by environment name")
+	}
+
+	if err := config.SetFile(""); err != nil {
+		t.Fatalf("Failed to set file: %v", err)
+	}
+	if _, ok := os.LookupEnv("spelling_db_host"); ok {
+		t.Error("Expected spelling_db_host to be removed with its file")
+	}
+}
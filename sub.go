@@ -0,0 +1,43 @@
+package config
+
+import "strings"
+
+// Sub returns a view of the keys below key, e.g. "database" or "services.api"
+//
+// The view reads the values of c, so Sub("database").Str("host") reads
+// database.host (DATABASE_HOST) and later reloads of c show up in the view.
+// Load, Reload, SetFile, Watch, Export and Snapshot act on the whole config
+// c belongs to. OnChange and OnKeyChange only report keys below key, named
+// relative to the view. Sub of a view narrows it further.
+func (c *Config) Sub(key string) *Config {
+	root, prefix := c, ""
+	if c.root != nil {
+		root, prefix = c.root, c.prefix
+	}
+	return &Config{
+		root:          root,
+		prefix:        prefix + toEnvKey(key) + "_",
+		timeLayouts:   root.timeLayouts,
+		listSeparator: root.listSeparator,
+	}
+}
+
+// rootKey returns the environment name of a key of a view in the Config it belongs to
+func (c *Config) rootKey(key string) string {
+	return c.prefix + toEnvKey(key)
+}
+
+// scoped returns the changes to keys below prefix, named relative to it
+func (cs ChangeSet) scoped(prefix string) ChangeSet {
+	scope := func(changes []Change) []Change {
+		var scoped []Change
+		for _, change := range changes {
+			if key, ok := strings.CutPrefix(change.Key, prefix); ok && key != "" {
+				change.Key = key
+				scoped = append(scoped, change)
+			}
+		}
+		return scoped
+	}
+	return ChangeSet{Added: scope(cs.Added), Removed: scope(cs.Removed), Modified: scope(cs.Modified)}
+}
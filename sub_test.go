@@ -0,0 +1,155 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestDotPathGet(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	content := `database:
+  host: db.local
+  port: 5432
+  replicas:
+    - host: r1
+    - host: r2
+  tags: [a, b]
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	config := NewWithOptions(path, WithIsolated())
+
+	if value := config.Get("database.host"); value != "db.local" {
+		t.Errorf("Expected database.host=db.local, got %#v", value)
+	}
+	if value := config.Get("database.replicas.1.host"); value != "r2" {
+		t.Errorf("Expected database.replicas.1.host=r2, got %#v", value)
+	}
+
+	expected := map[string]interface{}{
+		"host": "db.local",
+		"port": 5432,
+		"replicas": []interface{}{
+			map[string]interface{}{"host": "r1"},
+			map[string]interface{}{"host": "r2"},
+		},
+		"tags": []interface{}{"a", "b"},
+	}
+	if value := config.Get("database"); !reflect.DeepEqual(value, expected) {
+		t.Errorf("Expected the database subtree, got %#v", value)
+	}
+	if value := config.Get("DATABASE_REPLICAS"); !reflect.DeepEqual(value, expected["replicas"]) {
+		t.Errorf("Expected the replicas array, got %#v", value)
+	}
+	if value := config.Get("data"); value != nil {
+		t.Errorf("Expected a partial segment not to match, got %#v", value)
+	}
+}
+
+func TestSub(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	write := func(content string) {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write test file: %v", err)
+		}
+	}
+	write(`{"services": {"api": {"host": "api.local", "port": 8080, "timeout": "5s", "cache": {"size": 10}}}, "other": 1}`)
+
+	config := NewWithOptions(path, WithIsolated())
+	services := config.Sub("services")
+	api := services.Sub("api")
+
+	if value := api.Str("host"); value != "api.local" {
+		t.Errorf("Expected host=api.local, got %s", value)
+	}
+	if value := config.Sub("services.api").Int("PORT"); value != 8080 {
+		t.Errorf("Expected PORT=8080, got %d", value)
+	}
+	if value := api.Get("port"); value != json.Number("8080") {
+		t.Errorf("Expected port as json.Number, got %#v", value)
+	}
+	if value := api.Get("cache"); !reflect.DeepEqual(value, map[string]interface{}{"size": json.Number("10")}) {
+		t.Errorf("Expected the cache subtree, got %#v", value)
+	}
+	if value := Get[int](api.Sub("cache"), "size"); value != 10 {
+		t.Errorf("Expected cache size 10, got %d", value)
+	}
+	if _, ok := api.Lookup("other"); ok {
+		t.Error("Expected keys outside the view to be hidden")
+	}
+	if origin, ok := api.Origin("host"); !ok || origin != path {
+		t.Errorf("Expected host from %s, got %s", path, origin)
+	}
+
+	type API struct {
+		Host  string
+		Port  int
+		Cache struct {
+			Size int
+		}
+	}
+	var settings API
+	if err := api.Unmarshal(&settings); err != nil {
+		t.Fatalf("Failed to unmarshal view: %v", err)
+	}
+	if settings.Host != "api.local" || settings.Port != 8080 || settings.Cache.Size != 10 {
+		t.Errorf("Unexpected settings %+v", settings)
+	}
+
+	var changes []ChangeSet
+	api.OnChange(func(cs ChangeSet) { changes = append(changes, cs) })
+	var portChange Change
+	api.OnKeyChange("port", func(change Change) { portChange = change })
+
+	// A view follows reloads of its config, and reloading through it reloads the config
+	write(`{"services": {"api": {"host": "api.local", "port": 9090}}, "other": 2}`)
+	if err := api.Reload(); err != nil {
+		t.Fatalf("Failed to reload: %v", err)
+	}
+	if value := api.Int("port"); value != 9090 {
+		t.Errorf("Expected the view to see port 9090, got %d", value)
+	}
+	if value := config.Int("OTHER"); value != 2 {
+		t.Errorf("Expected the config to be reloaded, got OTHER=%d", value)
+	}
+	if len(changes) != 1 {
+		t.Fatalf("Expected one scoped change set, got %d", len(changes))
+	}
+	if cs := changes[0]; len(cs.Modified) != 1 || cs.Modified[0].Key != "PORT" || len(cs.Removed) != 2 {
+		t.Errorf("Expected PORT modified and the cache keys removed, got %+v", cs)
+	}
+	if portChange.Key != "PORT" || portChange.Old != "8080" || portChange.New != "9090" {
+		t.Errorf("Unexpected port change %+v", portChange)
+	}
+
+	// A snapshot of a view is a view of a snapshot
+	snapshot := api.Snapshot()
+	write(`{"services": {"api": {"host": "new.local"}}}`)
+	if err := config.Reload(); err != nil {
+		t.Fatalf("Failed to reload: %v", err)
+	}
+	if value := snapshot.Str("host"); value != "api.local" {
+		t.Errorf("Expected the snapshot to keep api.local, got %s", value)
+	}
+	if err := snapshot.Reload(); err == nil {
+		t.Error("Expected reloading a snapshot view to fail")
+	}
+}
+
+func TestOverriddenSpelling(t *testing.T) {
+	config := NewWithOptions("",
+		WithIsolated(),
+		WithSources(
+			DefaultsSource(map[string]interface{}{"db": map[string]interface{}{"host": "json.local", "port": 1}}),
+			DefaultsSource(map[string]interface{}{"DB_HOST": "env.local"}),
+		),
+	)
+
+	if value := config.Get("db"); !reflect.DeepEqual(value, map[string]interface{}{"HOST": "env.local", "port": 1}) {
+		t.Errorf("Expected only the winning spelling of DB_HOST, got %#v", value)
+	}
+}
@@ -0,0 +1,261 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	timeType     = reflect.TypeOf(time.Time{})
+)
+
+// FieldError describes a single struct field that could not be populated
+type FieldError struct {
+	Field string // Go field path, e.g. "Database.Port"
+	Key   string // Configuration key, e.g. "DATABASE_PORT"
+	Value string // Raw value that failed to convert
+	Err   error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s (%s=%q): %v", e.Field, e.Key, e.Value, e.Err)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// UnmarshalError lists every field that failed during Unmarshal
+type UnmarshalError struct {
+	Errors []*FieldError
+}
+
+func (e *UnmarshalError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("failed to unmarshal config: %s", strings.Join(msgs, "; "))
+}
+
+// Unmarshal populates the struct pointed to by v from the loaded configuration
+// Lists and times are read with the separator and layouts set by WithSeparator
+// and WithTimeLayouts, like the getters.
+func (c *Config) Unmarshal(v interface{}) error {
+	return unmarshal(v, c.lookup, c.valueFormat())
+}
+
+// Unmarshal populates the struct pointed to by v from environment variables
+//
+// Fields are matched by their `config:"KEY"` tag, or by the field name converted
+// to UPPER_SNAKE_CASE when the tag is missing. A tag of "-" skips the field and
+// `config:"KEY,required"` reports the field when its key is missing or empty.
+// Nested structs use their key as a prefix, so a Port field inside a Database
+// struct is read from DATABASE_PORT, matching the keys produced for nested
+// JSON/YAML config. Embedded structs without a tag share the parent prefix.
+// A `validate:"min=1,max=65535"` tag checks the value, or each element of a
+// list, against the rules min, max, oneof (space-separated values), regex
+// (last, as it takes the rest of the tag), url, hostport, email, port and
+// nonempty. A pointer to a struct type that is already being decoded, as in a
+// linked list, is left nil rather than followed without end.
+func Unmarshal(v interface{}) error {
+	return unmarshal(v, os.LookupEnv, valueFormat{})
+}
+
+// unmarshal decodes values returned by lookup into the struct pointed to by v
+func unmarshal(v interface{}, lookup func(string) (string, bool), format valueFormat) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("unmarshal target must be a non-nil pointer to a struct, got %T", v)
+	}
+
+	d := &structDecoder{lookup: lookup, format: format, active: make(map[reflect.Type]bool)}
+	d.decodeStruct(rv.Elem(), "", "")
+	if len(d.errs) > 0 {
+		return &UnmarshalError{Errors: d.errs}
+	}
+	return nil
+}
+
+// structDecoder walks a struct and collects conversion errors
+type structDecoder struct {
+	lookup func(string) (string, bool)
+	format valueFormat
+	active map[reflect.Type]bool // Struct types being decoded, to stop at recursive pointers
+	errs   []*FieldError
+}
+
+// decodeStruct populates the exported fields of rv and reports whether any value was found
+func (d *structDecoder) decodeStruct(rv reflect.Value, prefix, path string) bool {
+	found := false
+	rt := rv.Type()
+	d.active[rt] = true
+	defer delete(d.active, rt)
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(field.Tag.Get("config"), ",")
+		if name == "-" {
+			continue
+		}
+
+		fieldPath := field.Name
+		if path != "" {
+			fieldPath = path + "." + field.Name
+		}
+
+		// Untagged embedded structs share the parent prefix
+		if name == "" && field.Anonymous && isNestedStruct(indirectType(field.Type)) {
+			if d.decodeField(rv.Field(i), strings.TrimSuffix(prefix, "_"), fieldPath, true) {
+				found = true
+			}
+			continue
+		}
+
+		if name == "" {
+			name = toUpperSnake(field.Name)
+		}
+		rules, err := parseRules(field.Tag.Get("validate"))
+		if err != nil {
+			d.errs = append(d.errs, &FieldError{Field: fieldPath, Key: prefix + name, Err: fmt.Errorf("invalid validate tag: %w", err)})
+			continue
+		}
+
+		errs := len(d.errs)
+		if d.decodeField(rv.Field(i), prefix+name, fieldPath, false) {
+			found = true
+		} else if len(d.errs) == errs && hasTagOption(opts, "required") && !isNestedStruct(indirectType(field.Type)) {
+			d.errs = append(d.errs, &FieldError{Field: fieldPath, Key: prefix + name, Err: ErrNotFound})
+		}
+		if len(rules) > 0 && len(d.errs) == errs && !isNestedStruct(indirectType(field.Type)) {
+			d.checkRules(prefix+name, fieldPath, indirectType(field.Type), rules)
+		}
+	}
+	return found
+}
+
+// decodeField populates a single field and reports whether a value was found
+func (d *structDecoder) decodeField(fv reflect.Value, key, path string, embedded bool) bool {
+	ft := fv.Type()
+
+	if ft.Kind() == reflect.Pointer {
+		if !fv.IsNil() {
+			return d.decodeField(fv.Elem(), key, path, embedded)
+		}
+		if d.active[ft.Elem()] {
+			return false // A recursive type, e.g. Next *Node inside Node
+		}
+		elem := reflect.New(ft.Elem())
+		errs := len(d.errs)
+		if d.decodeField(elem.Elem(), key, path, embedded) {
+			fv.Set(elem)
+			return true
+		}
+		// Required fields of an optional struct only apply when the struct is present
+		kept := d.errs[:errs]
+		for _, err := range d.errs[errs:] {
+			if !errors.Is(err.Err, ErrNotFound) {
+				kept = append(kept, err)
+			}
+		}
+		d.errs = kept
+		return false
+	}
+
+	if isNestedStruct(ft) {
+		prefix := key + "_"
+		if embedded && key == "" {
+			prefix = ""
+		}
+		return d.decodeStruct(fv, prefix, path)
+	}
+
+	value, ok := d.lookup(key)
+	if !ok || value == "" {
+		return false
+	}
+	if err := setFieldValue(fv, value, d.format); err != nil {
+		d.errs = append(d.errs, &FieldError{Field: path, Key: key, Value: value, Err: err})
+		return false
+	}
+	return true
+}
+
+// checkRules reports the rules broken by the value of key, checking each element of a list
+func (d *structDecoder) checkRules(key, path string, ft reflect.Type, rules []Rule) {
+	value, ok := d.lookup(key)
+	if !ok {
+		return
+	}
+	values := []string{value}
+	if ft.Kind() == reflect.Slice && ft.Elem().Kind() != reflect.Uint8 && value != "" {
+		values, _ = splitList(value, d.format.listSeparator()) // Already converted without error
+	}
+	for _, v := range values {
+		for _, failed := range checkRules(v, rules) {
+			d.errs = append(d.errs, &FieldError{Field: path, Key: key, Value: value, Err: failed})
+		}
+	}
+}
+
+// setFieldValue converts value to the type of fv and stores it
+func setFieldValue(fv reflect.Value, value string, format valueFormat) error {
+	ft := fv.Type()
+	if err := decodeValue(fv, value, format); err != nil {
+		if errors.Is(err, errUnsupportedType) {
+			return fmt.Errorf("unsupported field type %s", ft)
+		}
+		return fmt.Errorf("cannot convert to %s: %w", ft, err)
+	}
+	return nil
+}
+
+// hasTagOption reports whether the comma-separated tag options include option
+func hasTagOption(opts, option string) bool {
+	for _, opt := range strings.Split(opts, ",") {
+		if strings.TrimSpace(opt) == option {
+			return true
+		}
+	}
+	return false
+}
+
+// isNestedStruct reports whether t is a struct whose fields map to prefixed keys
+// Structs that decode themselves, like time.Time, are read from a single key.
+func isNestedStruct(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t != timeType && !implementsDecoder(t)
+}
+
+// indirectType returns the element type of pointer types
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
+
+// toUpperSnake converts a Go field name like "MaxIdleConns" or "APIKey" to "MAX_IDLE_CONNS" or "API_KEY"
+func toUpperSnake(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
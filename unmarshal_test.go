@@ -0,0 +1,217 @@
+package config
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestUnmarshal(t *testing.T) {
+	yamlContent := `database:
+  host: db.internal
+  port: 5432
+  timeout: 5s
+  ssl: true
+  ratio: 0.75
+  replicas:
+    - db-1
+    - db-2
+cache:
+  ports: [6379, 6380]
+app_name: unmarshal-test
+`
+	err := createTestFile("unmarshal_test.yaml", yamlContent)
+	if err != nil {
+		t.Fatalf("Failed to create test yaml file: %v", err)
+	}
+	defer cleanupTestFile("unmarshal_test.yaml")
+
+	type Database struct {
+		Host     string        `config:"HOST"`
+		Port     int           `config:"PORT"`
+		Timeout  time.Duration `config:"TIMEOUT"`
+		SSL      *bool         `config:"SSL"`
+		Ratio    float64       `config:"RATIO"`
+		Replicas []string      `config:"REPLICAS"`
+		Missing  *string       `config:"MISSING"`
+	}
+	type Cache struct {
+		Ports []uint16
+	}
+	type Settings struct {
+		AppName  string
+		Database Database `config:"DATABASE"`
+		Cache    *Cache
+		Ignored  string `config:"-"`
+	}
+
+	config := New("unmarshal_test.yaml")
+
+	var settings Settings
+	if err := config.Unmarshal(&settings); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	if settings.AppName != "unmarshal-test" {
+		t.Errorf("Expected AppName=unmarshal-test, got %s", settings.AppName)
+	}
+	if settings.Database.Host != "db.internal" {
+		t.Errorf("Expected Database.Host=db.internal, got %s", settings.Database.Host)
+	}
+	if settings.Database.Port != 5432 {
+		t.Errorf("Expected Database.Port=5432, got %d", settings.Database.Port)
+	}
+	if settings.Database.Timeout != 5*time.Second {
+		t.Errorf("Expected Database.Timeout=5s, got %v", settings.Database.Timeout)
+	}
+	if settings.Database.SSL == nil || !*settings.Database.SSL {
+		t.Errorf("Expected Database.SSL=true, got %v", settings.Database.SSL)
+	}
+	if settings.Database.Ratio != 0.75 {
+		t.Errorf("Expected Database.Ratio=0.75, got %v", settings.Database.Ratio)
+	}
+	if !reflect.DeepEqual(settings.Database.Replicas, []string{"db-1", "db-2"}) {
+		t.Errorf("Expected Database.Replicas=[db-1 db-2], got %v", settings.Database.Replicas)
+	}
+	if settings.Database.Missing != nil {
+		t.Errorf("Expected Database.Missing=nil, got %v", *settings.Database.Missing)
+	}
+	if settings.Cache == nil || !reflect.DeepEqual(settings.Cache.Ports, []uint16{6379, 6380}) {
+		t.Errorf("Expected Cache.Ports=[6379 6380], got %+v", settings.Cache)
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	envContent := `UNMARSHAL_PORT=abc
+UNMARSHAL_DEBUG=maybe
+UNMARSHAL_NAME=ok
+`
+	err := createTestFile("unmarshal_errors.env", envContent)
+	if err != nil {
+		t.Fatalf("Failed to create test env file: %v", err)
+	}
+	defer cleanupTestFile("unmarshal_errors.env")
+
+	var settings struct {
+		Port  int    `config:"UNMARSHAL_PORT"`
+		Debug bool   `config:"UNMARSHAL_DEBUG"`
+		Name  string `config:"UNMARSHAL_NAME"`
+	}
+
+	config := New("unmarshal_errors.env")
+	err = config.Unmarshal(&settings)
+
+	var unmarshalErr *UnmarshalError
+	if !errors.As(err, &unmarshalErr) {
+		t.Fatalf("Expected *UnmarshalError, got %v", err)
+	}
+	if len(unmarshalErr.Errors) != 2 {
+		t.Fatalf("Expected 2 field errors, got %d: %v", len(unmarshalErr.Errors), err)
+	}
+	if unmarshalErr.Errors[0].Key != "UNMARSHAL_PORT" || unmarshalErr.Errors[1].Key != "UNMARSHAL_DEBUG" {
+		t.Errorf("Unexpected field errors: %v", err)
+	}
+	if settings.Name != "ok" {
+		t.Errorf("Expected Name=ok despite other failures, got %s", settings.Name)
+	}
+
+	if err := Unmarshal(settings); err == nil {
+		t.Errorf("Expected error for non-pointer target")
+	}
+}
+
+func TestUnmarshalRecursiveTypes(t *testing.T) {
+	type Node struct {
+		Name string
+		Next *Node
+	}
+	type Child struct {
+		Value  string
+		Parent *struct {
+			Name  string
+			Child *Child
+		}
+	}
+
+	config := NewWithOptions("",
+		WithIsolated(),
+		WithStruct(&Node{}), // Checked on every load
+		WithSources(DefaultsSource(map[string]interface{}{"name": "head", "value": "v", "parent": map[string]interface{}{"name": "p"}})),
+	)
+
+	var node Node
+	if err := config.Unmarshal(&node); err != nil {
+		t.Fatalf("Failed to unmarshal: %v", err)
+	}
+	if node.Name != "head" || node.Next != nil {
+		t.Errorf("Expected the recursive pointer to stay nil, got %+v", node)
+	}
+
+	var child Child
+	if err := config.Unmarshal(&child); err != nil {
+		t.Fatalf("Failed to unmarshal: %v", err)
+	}
+	if child.Value != "v" || child.Parent == nil || child.Parent.Name != "p" || child.Parent.Child != nil {
+		t.Errorf("Expected Parent to be decoded and Parent.Child to stay nil, got %+v", child)
+	}
+}
+
+func TestUnmarshalValueFormat(t *testing.T) {
+	type Settings struct {
+		Hosts   []string `config:"HOSTS"`
+		Ports   []int    `config:"PORTS" validate:"port"`
+		Labels  map[string]string
+		Started time.Time `config:"STARTED"`
+	}
+
+	config := NewWithOptions("",
+		WithIsolated(),
+		WithSeparator(";"),
+		WithTimeLayouts("2006-01-02"),
+		WithSources(DefaultsSource(map[string]interface{}{
+			"hosts":   "a;b",
+			"ports":   "80;443",
+			"labels":  "app=web;tier=db",
+			"started": "2024-05-01",
+		})),
+	)
+
+	var settings Settings
+	if err := config.Unmarshal(&settings); err != nil {
+		t.Fatalf("Failed to unmarshal: %v", err)
+	}
+	if !reflect.DeepEqual(settings.Hosts, config.StrSlice("HOSTS")) || len(settings.Hosts) != 2 {
+		t.Errorf("Expected HOSTS split on the configured separator, got %q", settings.Hosts)
+	}
+	if !reflect.DeepEqual(settings.Ports, []int{80, 443}) {
+		t.Errorf("Expected PORTS=[80 443], got %v", settings.Ports)
+	}
+	if !reflect.DeepEqual(settings.Labels, map[string]string{"app": "web", "tier": "db"}) {
+		t.Errorf("Expected LABELS map, got %v", settings.Labels)
+	}
+	if !settings.Started.Equal(time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected STARTED to use the configured layout, got %v", settings.Started)
+	}
+
+	// Rules check each element split on the same separator
+	bad := NewWithOptions("", WithIsolated(), WithSeparator(";"), WithSources(DefaultsSource(map[string]interface{}{"ports": "80;70000"})))
+	var unmarshalErr *UnmarshalError
+	if err := bad.Unmarshal(&settings); !errors.As(err, &unmarshalErr) || len(unmarshalErr.Errors) != 1 {
+		t.Fatalf("Expected one rule violation for PORTS, got %v", err)
+	}
+}
+
+func TestToUpperSnake(t *testing.T) {
+	tests := map[string]string{
+		"Host":         "HOST",
+		"MaxIdleConns": "MAX_IDLE_CONNS",
+		"APIKey":       "API_KEY",
+		"DBPort":       "DB_PORT",
+	}
+	for input, expected := range tests {
+		if got := toUpperSnake(input); got != expected {
+			t.Errorf("Expected %s for %s, got %s", expected, input, got)
+		}
+	}
+}
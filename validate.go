@@ -0,0 +1,188 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Violation describes a key that failed a check after loading
+type Violation struct {
+	Key     string // Configuration key, e.g. DATABASE_HOST
+	Field   string // Go field path when the check comes from a struct tag
+	Value   string // Raw value, empty when the key is missing
+	Rule    string // Failed rule, e.g. "required", "type" or "min"
+	Message string
+
+	// Set when the check comes from a schema, see WithSchema
+	File string // Path of the config file
+	Path string // JSON pointer into the document, e.g. /servers/0/port
+	Line int    // 1-based line number, 0 when unknown
+}
+
+func (v Violation) String() string {
+	if v.File == "" {
+		return fmt.Sprintf("%s %s", v.Key, v.Message)
+	}
+	location := v.File
+	if v.Line > 0 {
+		location = fmt.Sprintf("%s:%d", v.File, v.Line)
+	}
+	path := v.Path
+	if path == "" {
+		path = "document"
+	}
+	return fmt.Sprintf("%s: %s %s", location, path, v.Message)
+}
+
+// ValidationError lists every violation found while loading
+type ValidationError struct {
+	Violations []Violation
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		msgs[i] = v.String()
+	}
+	return fmt.Sprintf("invalid config: %s", strings.Join(msgs, "; "))
+}
+
+// Require marks keys that must be set to a non-empty value
+// They are checked by the next Load or MustLoad, even if the Config is
+// already loaded, and by every reload. Missing keys are reported together
+// in a *ValidationError.
+func (c *Config) Require(keys ...string) {
+	if c.root != nil {
+		for _, key := range keys {
+			c.root.Require(c.rootKey(key))
+		}
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.required = append(c.required, keys...)
+}
+
+// WithRequired marks keys that must be set to a non-empty value, see Config.Require
+func WithRequired(keys ...string) Option {
+	return func(c *Config) {
+		c.required = append(c.required, keys...)
+	}
+}
+
+// AddRule checks the value of key against rules, see Min, OneOf, Port, etc.
+// Rules are checked by the next Load or MustLoad and by every reload, a
+// reload that breaks one keeps the current values. Missing or empty keys are
+// skipped, except by NonEmpty, use Require to reject missing keys.
+func (c *Config) AddRule(key string, rules ...Rule) {
+	if c.root != nil {
+		c.root.AddRule(c.rootKey(key), rules...)
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.rules = append(c.rules, keyRules{key: key, rules: rules})
+}
+
+// WithRule checks the value of key against rules, see Config.AddRule
+func WithRule(key string, rules ...Rule) Option {
+	return func(c *Config) {
+		c.rules = append(c.rules, keyRules{key: key, rules: rules})
+	}
+}
+
+// keyRules holds the rules added for a key
+type keyRules struct {
+	key   string
+	rules []Rule
+}
+
+// WithStruct checks the struct pointed to by v on every load
+// Fields tagged `config:"KEY,required"` must be set to a non-empty value,
+// every value present must convert to its field's type and satisfy the rules
+// of its `validate:"..."` tag. The struct itself is not modified, use
+// Unmarshal to populate it.
+func WithStruct(v interface{}) Option {
+	return func(c *Config) {
+		c.structs = append(c.structs, v)
+	}
+}
+
+// validate checks a loaded state against the required keys, rules and structs, c.mu must be held
+func (c *Config) validate(state *loadState) error {
+	lookup := c.stateLookup(state)
+
+	var violations []Violation
+	reported := make(map[string]bool)
+	add := func(v Violation) {
+		if id := v.Key + "\x00" + v.Rule; !reported[id] {
+			reported[id] = true
+			violations = append(violations, v)
+		}
+	}
+
+	for _, key := range c.required {
+		if value, ok := lookup(key); !ok || strings.TrimSpace(value) == "" {
+			add(Violation{Key: key, Rule: "required", Message: "is required"})
+		}
+	}
+
+	for _, kr := range c.rules {
+		value, ok := lookup(kr.key)
+		if !ok {
+			continue
+		}
+		for _, failed := range checkRules(value, kr.rules) {
+			add(Violation{Key: kr.key, Value: value, Rule: failed.rule, Message: failed.Error()})
+		}
+	}
+
+	for _, target := range c.structs {
+		rt := reflect.TypeOf(target)
+		if rt == nil || rt.Kind() != reflect.Pointer {
+			return fmt.Errorf("struct to validate must be a pointer to a struct, got %T", target)
+		}
+		err := unmarshal(reflect.New(rt.Elem()).Interface(), lookup, c.valueFormat())
+		var unmarshalErr *UnmarshalError
+		if !errors.As(err, &unmarshalErr) {
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		for _, fe := range unmarshalErr.Errors {
+			var failed *ruleError
+			if errors.As(fe.Err, &failed) {
+				add(Violation{Key: fe.Key, Field: fe.Field, Value: fe.Value, Rule: failed.rule, Message: failed.Error()})
+				continue
+			}
+			if errors.Is(fe.Err, ErrNotFound) {
+				add(Violation{Key: fe.Key, Field: fe.Field, Rule: "required", Message: "is required"})
+				continue
+			}
+			add(Violation{Key: fe.Key, Field: fe.Field, Value: fe.Value, Rule: "type", Message: fe.Err.Error()})
+		}
+	}
+
+	if len(violations) > 0 {
+		return &ValidationError{Violations: violations}
+	}
+	return nil
+}
+
+// stateLookup returns a lookup reading state the way lookup will once it is current, c.mu must be held
+func (c *Config) stateLookup(state *loadState) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		if value, ok := state.values[toEnvKey(key)]; ok {
+			return value, true
+		}
+		if !c.isolated || c.envFallback {
+			return c.lookupEnv(key)
+		}
+		return "", false
+	}
+}
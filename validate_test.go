@@ -0,0 +1,360 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRequiredKeys(t *testing.T) {
+	config := NewWithOptions("",
+		WithIsolated(),
+		WithRequired("DB_HOST", "DB_USER", "DB_PASSWORD"),
+		WithSources(DefaultsSource(map[string]interface{}{
+			"db_host":     "localhost",
+			"db_password": " ",
+		})),
+	)
+
+	err := config.Load()
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Expected *ValidationError, got %v", err)
+	}
+	if len(validationErr.Violations) != 2 {
+		t.Fatalf("Expected 2 violations, got %v", validationErr.Violations)
+	}
+	for i, key := range []string{"DB_USER", "DB_PASSWORD"} {
+		if v := validationErr.Violations[i]; v.Key != key || v.Rule != "required" {
+			t.Errorf("Expected %s to be reported as required, got %+v", key, v)
+		}
+	}
+	if err.Error() != "invalid config: DB_USER is required; DB_PASSWORD is required" {
+		t.Errorf("Unexpected error message %q", err.Error())
+	}
+
+	defer func() {
+		if r := recover(); r == nil || !strings.Contains(r.(string), "DB_USER is required") {
+			t.Errorf("Expected MustLoad to panic with the report, got %v", r)
+		}
+	}()
+	config.MustLoad()
+}
+
+func TestRequireOnLoadedConfig(t *testing.T) {
+	config := NewWithOptions("",
+		WithIsolated(),
+		WithSources(DefaultsSource(map[string]interface{}{"app_name": "demo"})),
+	)
+
+	config.Require("APP_NAME")
+	if err := config.Load(); err != nil {
+		t.Fatalf("Expected APP_NAME to satisfy Require, got %v", err)
+	}
+	config.Require("APP_SECRET")
+	if err := config.Load(); err == nil || !strings.Contains(err.Error(), "APP_SECRET is required") {
+		t.Errorf("Expected Load to report APP_SECRET, got %v", err)
+	}
+	if value := config.Str("APP_NAME"); value != "demo" {
+		t.Errorf("Expected a failed check to keep the loaded values, got %s", value)
+	}
+}
+
+func TestWithStruct(t *testing.T) {
+	type Database struct {
+		Host string `config:"HOST,required"`
+		Port int    `config:"PORT,required"`
+	}
+	type Cache struct {
+		URL string `config:"URL,required"`
+	}
+	type Settings struct {
+		Name     string `config:"APP_NAME,required"`
+		Workers  int
+		Database Database
+		Cache    *Cache // Optional, only checked when a CACHE_ key is set
+	}
+
+	config := NewWithOptions("",
+		WithIsolated(),
+		WithStruct(&Settings{}),
+		WithSources(DefaultsSource(map[string]interface{}{
+			"workers":  "many",
+			"database": map[string]interface{}{"port": "x"},
+		})),
+	)
+
+	err := config.Load()
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Expected *ValidationError, got %v", err)
+	}
+
+	expected := map[string]string{
+		"APP_NAME":      "required",
+		"WORKERS":       "type",
+		"DATABASE_HOST": "required",
+		"DATABASE_PORT": "type",
+	}
+	if len(validationErr.Violations) != len(expected) {
+		t.Fatalf("Expected %d violations, got %v", len(expected), validationErr.Violations)
+	}
+	for _, v := range validationErr.Violations {
+		if rule, ok := expected[v.Key]; !ok || rule != v.Rule {
+			t.Errorf("Unexpected violation %+v", v)
+		}
+	}
+	if v := validationErr.Violations[1]; v.Field != "Workers" || v.Value != "many" {
+		t.Errorf("Expected the WORKERS violation to name its field and value, got %+v", v)
+	}
+}
+
+func TestUnmarshalRequired(t *testing.T) {
+	type Cache struct {
+		URL  string `config:"URL,required"`
+		Size int    `config:"SIZE"`
+	}
+	type Settings struct {
+		Name  string `config:"NAME,required"`
+		Cache *Cache `config:"CACHE"`
+	}
+
+	config := NewWithOptions("",
+		WithIsolated(),
+		WithSources(DefaultsSource(map[string]interface{}{"cache_size": "10"})),
+	)
+
+	var settings Settings
+	err := config.Unmarshal(&settings)
+	var unmarshalErr *UnmarshalError
+	if !errors.As(err, &unmarshalErr) || len(unmarshalErr.Errors) != 2 {
+		t.Fatalf("Expected NAME and CACHE_URL to be reported, got %v", err)
+	}
+	for _, fe := range unmarshalErr.Errors {
+		if !errors.Is(fe, ErrNotFound) {
+			t.Errorf("Expected %s to wrap ErrNotFound, got %v", fe.Key, fe.Err)
+		}
+	}
+
+	// Required fields of an absent optional struct are not reported
+	empty := NewWithOptions("", WithIsolated(), WithSources(DefaultsSource(map[string]interface{}{"name": "demo"})))
+	settings = Settings{}
+	if err := empty.Unmarshal(&settings); err != nil {
+		t.Errorf("Expected an absent *Cache to be skipped, got %v", err)
+	}
+	if settings.Cache != nil {
+		t.Errorf("Expected Cache to stay nil, got %+v", settings.Cache)
+	}
+}
+
+func TestReloadKeepsStateOnMissingRequired(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "required.env")
+	if err := os.WriteFile(path, []byte("API_TOKEN=abc\n"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	config := NewWithOptions(path, WithIsolated(), WithRequired("API_TOKEN"))
+	if err := config.Load(); err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte("OTHER=1\n"), 0644); err != nil {
+		t.Fatalf("Failed to update test file: %v", err)
+	}
+	var validationErr *ValidationError
+	if err := config.Reload(); !errors.As(err, &validationErr) {
+		t.Fatalf("Expected Reload to fail with *ValidationError, got %v", err)
+	}
+	if value := config.Str("API_TOKEN"); value != "abc" {
+		t.Errorf("Expected API_TOKEN to keep abc, got %s", value)
+	}
+	if _, ok := config.Lookup("OTHER"); ok {
+		t.Error("Expected the rejected reload not to add OTHER")
+	}
+}
+
+func TestRules(t *testing.T) {
+	tests := []struct {
+		rule    Rule
+		valid   []string
+		invalid []string
+	}{
+		{Min(1), []string{"1", " 2 ", "1.5"}, []string{"0", "-3", "abc"}},
+		{Max(10), []string{"10", "-1"}, []string{"10.5", "x"}},
+		{OneOf("debug", "info"), []string{"debug", " info "}, []string{"DEBUG", "warn"}},
+		{Regex(`^[a-z]+$`), []string{"abc"}, []string{"ab1", "ABC"}},
+		{URL(), []string{"https://example.com/path", "postgres://user@db:5432/app"}, []string{"example.com", "/path", "http://"}},
+		{HostPort(), []string{"localhost:8080", ":80", "[::1]:443"}, []string{"localhost", "host:0", "host:http"}},
+		{Email(), []string{"user@example.com"}, []string{"user", "User <user@example.com>", "@example.com"}},
+		{Port(), []string{"1", "65535", " 8080 "}, []string{"0", "65536", "-1", "http"}},
+		{NonEmpty(), []string{"x"}, []string{"", "  "}},
+	}
+
+	for _, test := range tests {
+		for _, value := range test.valid {
+			if err := test.rule.Check(value); err != nil {
+				t.Errorf("Expected %s to accept %q, got %v", test.rule.Name, value, err)
+			}
+		}
+		for _, value := range test.invalid {
+			if err := test.rule.Check(value); err == nil {
+				t.Errorf("Expected %s to reject %q", test.rule.Name, value)
+			}
+		}
+	}
+}
+
+func TestParseRules(t *testing.T) {
+	rules, err := parseRules("min=1, max=65535,oneof=a b c,nonempty,regex=^[a-z]{1,3}$")
+	if err != nil {
+		t.Fatalf("Failed to parse rules: %v", err)
+	}
+	var names []string
+	for _, rule := range rules {
+		names = append(names, rule.Name)
+	}
+	if strings.Join(names, ",") != "min,max,oneof,nonempty,regex" {
+		t.Errorf("Unexpected rules %v", names)
+	}
+	if err := rules[4].Check("abcd"); err == nil {
+		t.Error("Expected the regex to keep its comma")
+	}
+
+	for _, tag := range []string{"min", "min=x", "port=1", "between=1 2", "regex=("} {
+		if _, err := parseRules(tag); err == nil {
+			t.Errorf("Expected parseRules(%q) to fail", tag)
+		}
+	}
+}
+
+func TestAddRule(t *testing.T) {
+	config := NewWithOptions("",
+		WithIsolated(),
+		WithRule("server.port", Port()),
+		WithRule("LOG_LEVEL", OneOf("debug", "info", "warn")),
+		WithRule("WORKERS", Min(1), Max(8)),
+		WithRule("OPTIONAL_URL", URL()),
+		WithSources(DefaultsSource(map[string]interface{}{
+			"server":    map[string]interface{}{"port": "70000"},
+			"log_level": "verbose",
+			"workers":   "16",
+			"name":      "",
+		})),
+	)
+
+	err := config.Load()
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Expected *ValidationError, got %v", err)
+	}
+	expected := []Violation{
+		{Key: "server.port", Value: "70000", Rule: "port", Message: "must be a port number between 1 and 65535"},
+		{Key: "LOG_LEVEL", Value: "verbose", Rule: "oneof", Message: "must be one of debug, info, warn"},
+		{Key: "WORKERS", Value: "16", Rule: "max", Message: "must be at most 8"},
+	}
+	if len(validationErr.Violations) != len(expected) {
+		t.Fatalf("Expected %d violations, got %v", len(expected), validationErr.Violations)
+	}
+	for i, v := range validationErr.Violations {
+		if v != expected[i] {
+			t.Errorf("Expected violation %+v, got %+v", expected[i], v)
+		}
+	}
+
+	// Empty values are skipped except by NonEmpty
+	config.AddRule("NAME", Min(1))
+	if err := config.Load(); !errors.As(err, &validationErr) || len(validationErr.Violations) != 3 {
+		t.Errorf("Expected an empty NAME to be skipped by min, got %v", err)
+	}
+	config.AddRule("NAME", NonEmpty())
+	if err := config.Load(); !errors.As(err, &validationErr) || validationErr.Violations[3].Rule != "nonempty" {
+		t.Errorf("Expected an empty NAME to break nonempty, got %v", err)
+	}
+}
+
+func TestStructValidateTag(t *testing.T) {
+	type Server struct {
+		Host  string   `config:"HOST" validate:"nonempty"`
+		Port  int      `config:"PORT" validate:"port"`
+		Ports []int    `config:"PORTS" validate:"min=1024"`
+		Mode  string   `config:"MODE" validate:"oneof=dev prod"`
+		Admin string   `config:"ADMIN" validate:"email"`
+		Tags  []string `config:"TAGS" validate:"regex=^[a-z]+$"`
+	}
+	type Settings struct {
+		Server Server `config:"SERVER"`
+	}
+
+	config := NewWithOptions("",
+		WithIsolated(),
+		WithSources(DefaultsSource(map[string]interface{}{
+			"server": map[string]interface{}{
+				"host":  "",
+				"port":  "0",
+				"ports": []interface{}{8080, 80},
+				"mode":  "prod",
+				"admin": "root",
+				"tags":  "web,db",
+			},
+		})),
+	)
+
+	var settings Settings
+	err := config.Unmarshal(&settings)
+	var unmarshalErr *UnmarshalError
+	if !errors.As(err, &unmarshalErr) {
+		t.Fatalf("Expected *UnmarshalError, got %v", err)
+	}
+	keys := make([]string, len(unmarshalErr.Errors))
+	for i, fe := range unmarshalErr.Errors {
+		keys[i] = fe.Key
+	}
+	if strings.Join(keys, ",") != "SERVER_HOST,SERVER_PORT,SERVER_PORTS,SERVER_ADMIN" {
+		t.Errorf("Unexpected failing keys %v", keys)
+	}
+	if settings.Server.Port != 0 || settings.Server.Mode != "prod" {
+		t.Errorf("Expected valid fields to be populated, got %+v", settings.Server)
+	}
+
+	// WithStruct reports the same rules as violations
+	checked := NewWithOptions("",
+		WithIsolated(),
+		WithStruct(&Settings{}),
+		WithSources(DefaultsSource(map[string]interface{}{"server": map[string]interface{}{"port": "99999", "mode": "test"}})),
+	)
+	var validationErr *ValidationError
+	if err := checked.Load(); !errors.As(err, &validationErr) || len(validationErr.Violations) != 2 {
+		t.Fatalf("Expected 2 violations, got %v", err)
+	}
+	if v := validationErr.Violations[0]; v.Field != "Server.Port" || v.Rule != "port" || v.Value != "99999" {
+		t.Errorf("Unexpected violation %+v", v)
+	}
+	if v := validationErr.Violations[1]; v.Rule != "oneof" || v.Message != "must be one of dev, prod" {
+		t.Errorf("Unexpected violation %+v", v)
+	}
+}
+
+func TestReloadKeepsStateOnBrokenRule(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.env")
+	if err := os.WriteFile(path, []byte("POOL_SIZE=4\n"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	config := NewWithOptions(path, WithIsolated(), WithRule("POOL_SIZE", Min(1), Max(32)))
+	if err := config.Load(); err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte("POOL_SIZE=64\n"), 0644); err != nil {
+		t.Fatalf("Failed to update test file: %v", err)
+	}
+	var validationErr *ValidationError
+	if err := config.Reload(); !errors.As(err, &validationErr) || validationErr.Violations[0].Rule != "max" {
+		t.Fatalf("Expected Reload to fail on max, got %v", err)
+	}
+	if value := config.Int("POOL_SIZE"); value != 4 {
+		t.Errorf("Expected POOL_SIZE to keep 4, got %d", value)
+	}
+}
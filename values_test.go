@@ -0,0 +1,136 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestNativeValues(t *testing.T) {
+	dir := t.TempDir()
+	jsonPath := filepath.Join(dir, "config.json")
+	yamlPath := filepath.Join(dir, "config.yaml")
+	tomlPath := filepath.Join(dir, "config.toml")
+
+	files := map[string]string{
+		jsonPath: `{"json": {"big": 1000000, "pi": 3.14159265358979323846, "exp": 1e3, "on": true, "none": null, "ids": [1, 2.5]}}`,
+		yamlPath: "yaml:\n  big: 1000000.0\n  small: 0.000001\n  count: 3\n  none: ~\n  flags: [true, false]\n",
+		tomlPath: "[toml]\nbig = 9007199254740993\nratio = 0.5\n",
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+	}
+
+	config := NewWithOptions(jsonPath,
+		WithIsolated(),
+		WithSources(FileSource(yamlPath), FileSource(tomlPath), DefaultsSource(map[string]interface{}{"defaults": 2.50})),
+	)
+	if err := config.Load(); err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	formatted := map[string]string{
+		"JSON_BIG":   "1000000",
+		"JSON_PI":    "3.14159265358979323846",
+		"JSON_EXP":   "1e3",
+		"JSON_ON":    "true",
+		"JSON_NONE":  "",
+		"JSON_IDS":   "1,2.5",
+		"YAML_BIG":   "1000000",
+		"YAML_SMALL": "0.000001",
+		"YAML_NONE":  "",
+		"YAML_FLAGS": "true,false",
+		"TOML_BIG":   "9007199254740993",
+		"TOML_RATIO": "0.5",
+		"DEFAULTS":   "2.5",
+		"YAML_COUNT": "3",
+	}
+	for key, expected := range formatted {
+		if value, _ := config.Lookup(key); value != expected {
+			t.Errorf("Expected %s=%q, got %q", key, expected, value)
+		}
+	}
+	if value := config.Int("JSON_BIG"); value != 1000000 {
+		t.Errorf("Expected JSON_BIG to read as an int, got %d", value)
+	}
+	if value := config.Int64("TOML_BIG"); value != 9007199254740993 {
+		t.Errorf("Expected TOML_BIG to keep its precision, got %d", value)
+	}
+	if _, ok := config.Lookup("YAML_NONE"); !ok {
+		t.Error("Expected a null value to be set")
+	}
+
+	typed := map[string]interface{}{
+		"json.big":   json.Number("1000000"),
+		"JSON_ON":    true,
+		"json.ids":   []interface{}{json.Number("1"), json.Number("2.5")},
+		"YAML_BIG":   1000000.0,
+		"yaml.count": 3,
+		"toml.big":   int64(9007199254740993),
+		"DEFAULTS":   2.5,
+	}
+	for key, expected := range typed {
+		if value := config.Get(key); !reflect.DeepEqual(value, expected) {
+			t.Errorf("Expected Get(%q) = %#v, got %#v", key, expected, value)
+		}
+	}
+	for _, key := range []string{"JSON_NONE", "YAML_NONE", "MISSING"} {
+		if value := config.Get(key); value != nil {
+			t.Errorf("Expected Get(%q) = nil, got %#v", key, value)
+		}
+	}
+}
+
+func TestNativeValuesExport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "export.yaml")
+	if err := os.WriteFile(path, []byte("native_export:\n  size: 2000000.0\n  none: null\n  on: yes\n"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	defer os.Unsetenv("NATIVE_EXPORT_SIZE")
+	defer os.Unsetenv("NATIVE_EXPORT_NONE")
+	defer os.Unsetenv("NATIVE_EXPORT_ON")
+
+	config := New(path)
+	if err := config.Load(); err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	exported := map[string]string{
+		"NATIVE_EXPORT_SIZE": "2000000",
+		"NATIVE_EXPORT_NONE": "",
+		"NATIVE_EXPORT_ON":   "yes", // YAML 1.2 keeps yes as a string
+	}
+	for key, expected := range exported {
+		if value, ok := os.LookupEnv(key); !ok || value != expected {
+			t.Errorf("Expected exported %s=%q, got %q (set: %v)", key, expected, value, ok)
+		}
+	}
+
+	os.Setenv("NATIVE_EXPORT_OTHER", "x")
+	defer os.Unsetenv("NATIVE_EXPORT_OTHER")
+	if value := config.Get("NATIVE_EXPORT_OTHER"); value != "x" {
+		t.Errorf("Expected Get to fall back to the environment, got %#v", value)
+	}
+}
+
+func TestJSONTrailingData(t *testing.T) {
+	if _, err := loadJSONConfig([]byte("{\"a\": 1}\n{\"b\": 2}"), loadOptions{}); err == nil {
+		t.Fatal("Expected trailing data to be rejected")
+	} else {
+		var parseErr *ParseError
+		if !errors.As(err, &parseErr) || parseErr.Line != 2 {
+			t.Errorf("Expected a *ParseError on line 2, got %v", err)
+		}
+	}
+	if _, err := loadJSONConfig([]byte(""), loadOptions{}); err == nil {
+		t.Error("Expected an empty document to be rejected")
+	}
+	if _, err := loadJSONConfig([]byte("{\"a\": 1}\n\n"), loadOptions{}); err != nil {
+		t.Errorf("Expected trailing whitespace to be accepted, got %v", err)
+	}
+}
@@ -0,0 +1,222 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+const (
+	defaultWatchDebounce = 100 * time.Millisecond
+	defaultPollInterval  = time.Second
+)
+
+// watchSettings configures Watch
+type watchSettings struct {
+	onError  func(error)   // Receives failed reloads and watcher errors
+	debounce time.Duration // Quiet period before reloading after a change
+	poll     time.Duration // Polling interval, forces polling when set
+}
+
+// watchedSource is implemented by sources backed by files
+type watchedSource interface {
+	watchFiles() []string
+}
+
+func (s *fileSource) watchFiles() []string {
+	return []string{s.path}
+}
+
+func (d *Dotenv) watchFiles() []string {
+	return d.Paths()
+}
+
+// Watch reloads the Config whenever one of its files changes, until ctx is done
+//
+// Files are watched through inotify and the other fsnotify backends, falling
+// back to polling when those are unavailable. Their directories are watched
+// rather than the files themselves, so editors that save by renaming a new
+// file over the old one and Kubernetes ConfigMap symlink swaps are detected.
+// Bursts of writes are debounced into a single Reload. A reload that fails
+// keeps the previous values and is reported to the handler set with
+// WithWatchErrorHandler.
+//
+// The watched files are those of the config file and sources when Watch is
+// called. Watch returns once watching has started.
+func (c *Config) Watch(ctx context.Context) error {
+	if c.root != nil {
+		return c.root.Watch(ctx)
+	}
+	if c.frozen {
+		return errSnapshot
+	}
+	files := c.watchFiles()
+	if len(files) == 0 {
+		return errors.New("failed to watch config: no config files")
+	}
+
+	settings := c.watch
+	if settings.debounce <= 0 {
+		settings.debounce = defaultWatchDebounce
+	}
+
+	w := &watcher{config: c, files: files, settings: settings, seen: stampFiles(files)}
+	if settings.poll <= 0 {
+		if notify, err := w.notifier(); err == nil {
+			go w.runNotify(ctx, notify)
+			return nil
+		}
+		settings.poll = defaultPollInterval
+	}
+	go w.runPoll(ctx, settings.poll)
+	return nil
+}
+
+// watchFiles returns the files backing the config file and sources
+func (c *Config) watchFiles() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var files []string
+	for _, source := range c.sources() {
+		if watched, ok := source.(watchedSource); ok {
+			files = append(files, watched.watchFiles()...)
+		}
+	}
+	return files
+}
+
+// fileStamp identifies the content of a watched file
+type fileStamp struct {
+	target  string // Path after resolving symlinks
+	size    int64
+	modTime int64
+	exists  bool
+}
+
+// stampFiles returns the current stamp of each file
+func stampFiles(files []string) []fileStamp {
+	stamps := make([]fileStamp, len(files))
+	for i, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			continue
+		}
+		target, err := filepath.EvalSymlinks(file)
+		if err != nil {
+			target = file
+		}
+		stamps[i] = fileStamp{target: target, size: info.Size(), modTime: info.ModTime().UnixNano(), exists: true}
+	}
+	return stamps
+}
+
+// watcher reloads a Config when the stamps of its files change
+type watcher struct {
+	config   *Config
+	files    []string
+	settings watchSettings
+	seen     []fileStamp // Stamps of the last reload
+}
+
+// notifier returns an fsnotify watcher for the directories of the watched files
+func (w *watcher) notifier() (*fsnotify.Watcher, error) {
+	notify, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	added := make(map[string]bool)
+	for _, file := range w.files {
+		dir := filepath.Dir(file)
+		if added[dir] {
+			continue
+		}
+		if err := notify.Add(dir); err != nil {
+			notify.Close()
+			return nil, err
+		}
+		added[dir] = true
+	}
+	return notify, nil
+}
+
+// runNotify reloads after each burst of file system events
+func (w *watcher) runNotify(ctx context.Context, notify *fsnotify.Watcher) {
+	defer notify.Close()
+
+	timer := time.NewTimer(w.settings.debounce)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case _, ok := <-notify.Events:
+			if !ok {
+				return
+			}
+			// Any event in the directories may swap a file, the stamps tell if one changed
+			timer.Reset(w.settings.debounce)
+		case err, ok := <-notify.Errors:
+			if !ok {
+				return
+			}
+			w.report(err)
+		case <-timer.C:
+			w.check()
+		}
+	}
+}
+
+// runPoll compares the stamps of the files at every interval
+func (w *watcher) runPoll(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	timer := time.NewTimer(w.settings.debounce)
+	timer.Stop()
+	defer timer.Stop()
+
+	polled := w.seen
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// Keep waiting while the files are still being written
+			if stamps := stampFiles(w.files); !slices.Equal(stamps, polled) {
+				polled = stamps
+				timer.Reset(w.settings.debounce)
+			}
+		case <-timer.C:
+			w.check()
+		}
+	}
+}
+
+// check reloads the Config if any file changed since the last reload
+func (w *watcher) check() {
+	stamps := stampFiles(w.files)
+	if slices.Equal(stamps, w.seen) {
+		return
+	}
+	// A failed reload is reported once, the next change retries
+	w.seen = stamps
+	if err := w.config.Reload(); err != nil {
+		w.report(err)
+	}
+}
+
+// report passes an error to the error handler, if any
+func (w *watcher) report(err error) {
+	if w.settings.onError != nil {
+		w.settings.onError(err)
+	}
+}
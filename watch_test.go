@@ -0,0 +1,230 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// waitFor polls cond until it holds or the timeout expires
+func waitFor(t *testing.T, timeout time.Duration, cond func() bool) bool {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return true
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return cond()
+}
+
+// errorRecorder collects errors reported by Watch
+type errorRecorder struct {
+	mu   sync.Mutex
+	errs []error
+}
+
+func (r *errorRecorder) record(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.errs = append(r.errs, err)
+}
+
+func (r *errorRecorder) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.errs)
+}
+
+func TestWatchReloadsOnWrite(t *testing.T) {
+	for name, opts := range map[string][]Option{
+		"notify":  nil,
+		"polling": {WithWatchPolling(20 * time.Millisecond)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "watch.yaml")
+			if err := os.WriteFile(path, []byte("watch:\n  level: one\n"), 0644); err != nil {
+				t.Fatalf("Failed to create test file: %v", err)
+			}
+
+			opts = append(opts, WithIsolated(), WithWatchDebounce(20*time.Millisecond))
+			config := NewWithOptions(path, opts...)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if err := config.Watch(ctx); err != nil {
+				t.Fatalf("Failed to watch config: %v", err)
+			}
+
+			if err := os.WriteFile(path, []byte("watch:\n  level: two\n"), 0644); err != nil {
+				t.Fatalf("Failed to update test file: %v", err)
+			}
+			if !waitFor(t, 2*time.Second, func() bool { return config.Str("WATCH_LEVEL") == "two" }) {
+				t.Errorf("Expected WATCH_LEVEL=two after write, got %s", config.Str("WATCH_LEVEL"))
+			}
+		})
+	}
+}
+
+func TestWatchRenameAndSymlinkSwap(t *testing.T) {
+	dir := t.TempDir()
+
+	// Kubernetes mounts ConfigMaps as config.env -> ..data/config.env, ..data -> ..v1
+	for _, version := range []string{"..v1", "..v2"} {
+		if err := os.Mkdir(filepath.Join(dir, version), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		content := "SWAP_VERSION=" + version[2:] + "\n"
+		if err := os.WriteFile(filepath.Join(dir, version, "config.env"), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+	}
+	if err := os.Symlink("..v1", filepath.Join(dir, "..data")); err != nil {
+		t.Skipf("Symlinks not supported: %v", err)
+	}
+	if err := os.Symlink(filepath.Join("..data", "config.env"), filepath.Join(dir, "config.env")); err != nil {
+		t.Fatalf("Failed to create symlink: %v", err)
+	}
+
+	renamed := filepath.Join(dir, "renamed.env")
+	if err := os.WriteFile(renamed, []byte("SWAP_EDITOR=old\n"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	config := NewWithOptions(filepath.Join(dir, "config.env"),
+		WithIsolated(),
+		WithWatchDebounce(20*time.Millisecond),
+		WithSources(FileSource(renamed)),
+	)
+	if config.Str("SWAP_VERSION") != "v1" {
+		t.Fatalf("Expected SWAP_VERSION=v1, got %s", config.Str("SWAP_VERSION"))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := config.Watch(ctx); err != nil {
+		t.Fatalf("Failed to watch config: %v", err)
+	}
+
+	// Swap the ..data symlink atomically like the kubelet
+	tmpLink := filepath.Join(dir, "..data_tmp")
+	if err := os.Symlink("..v2", tmpLink); err != nil {
+		t.Fatalf("Failed to create symlink: %v", err)
+	}
+	if err := os.Rename(tmpLink, filepath.Join(dir, "..data")); err != nil {
+		t.Fatalf("Failed to swap symlink: %v", err)
+	}
+	if !waitFor(t, 2*time.Second, func() bool { return config.Str("SWAP_VERSION") == "v2" }) {
+		t.Errorf("Expected SWAP_VERSION=v2 after symlink swap, got %s", config.Str("SWAP_VERSION"))
+	}
+
+	// Save through a temporary file renamed over the original like many editors
+	tmpFile := filepath.Join(dir, "renamed.env.tmp")
+	if err := os.WriteFile(tmpFile, []byte("SWAP_EDITOR=new\n"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	if err := os.Rename(tmpFile, renamed); err != nil {
+		t.Fatalf("Failed to rename test file: %v", err)
+	}
+	if !waitFor(t, 2*time.Second, func() bool { return config.Str("SWAP_EDITOR") == "new" }) {
+		t.Errorf("Expected SWAP_EDITOR=new after rename, got %s", config.Str("SWAP_EDITOR"))
+	}
+}
+
+func TestWatchKeepsValuesOnFailedReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "watch.json")
+	if err := os.WriteFile(path, []byte(`{"watch_fail": {"host": "good.local"}}`), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	recorder := &errorRecorder{}
+	config := NewWithOptions(path,
+		WithWatchDebounce(20*time.Millisecond),
+		WithWatchErrorHandler(recorder.record),
+	)
+	defer os.Unsetenv("WATCH_FAIL_HOST")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := config.Watch(ctx); err != nil {
+		t.Fatalf("Failed to watch config: %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"watch_fail": {"host": `), 0644); err != nil {
+		t.Fatalf("Failed to update test file: %v", err)
+	}
+	if !waitFor(t, 2*time.Second, func() bool { return recorder.count() > 0 }) {
+		t.Fatalf("Expected the failed reload to be reported")
+	}
+	if value := config.Str("WATCH_FAIL_HOST"); value != "good.local" {
+		t.Errorf("Expected WATCH_FAIL_HOST to keep good.local, got %s", value)
+	}
+	if value := os.Getenv("WATCH_FAIL_HOST"); value != "good.local" {
+		t.Errorf("Expected exported WATCH_FAIL_HOST to keep good.local, got %s", value)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"watch_fail": {"host": "fixed.local"}}`), 0644); err != nil {
+		t.Fatalf("Failed to update test file: %v", err)
+	}
+	if !waitFor(t, 2*time.Second, func() bool { return config.Str("WATCH_FAIL_HOST") == "fixed.local" }) {
+		t.Errorf("Expected WATCH_FAIL_HOST=fixed.local after fixing the file, got %s", config.Str("WATCH_FAIL_HOST"))
+	}
+}
+
+func TestWatchDebounce(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "debounce.env")
+	if err := os.WriteFile(path, []byte("DEBOUNCE_N=0\n"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	source := &countingSource{Source: FileSource(path)}
+	config := NewWithOptions("", WithIsolated(), WithWatchDebounce(200*time.Millisecond), WithSources(source))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := config.Watch(ctx); err != nil {
+		t.Fatalf("Failed to watch config: %v", err)
+	}
+
+	for i := 1; i <= 5; i++ {
+		if err := os.WriteFile(path, []byte(fmt.Sprintf("DEBOUNCE_N=%d\n", i)), 0644); err != nil {
+			t.Fatalf("Failed to update test file: %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !waitFor(t, 2*time.Second, func() bool { return config.Int("DEBOUNCE_N") == 5 }) {
+		t.Fatalf("Expected DEBOUNCE_N=5, got %d", config.Int("DEBOUNCE_N"))
+	}
+	if loads := source.count(); loads != 2 {
+		t.Errorf("Expected the burst to cause a single reload, got %d loads", loads-1)
+	}
+}
+
+// countingSource counts how often a source is loaded
+type countingSource struct {
+	Source
+	mu    sync.Mutex
+	loads int
+}
+
+func (s *countingSource) Load() (map[string]interface{}, error) {
+	s.mu.Lock()
+	s.loads++
+	s.mu.Unlock()
+	return s.Source.Load()
+}
+
+func (s *countingSource) watchFiles() []string {
+	return s.Source.(watchedSource).watchFiles()
+}
+
+func (s *countingSource) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.loads
+}
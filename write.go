@@ -0,0 +1,122 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// AllNested returns the loaded values as a nested map, the inverse of flattening
+// Paths like "database.host" become nested maps and indexed keys become
+// arrays again. Values keep the type they were decoded with, see Get. Keys
+// from .env files and the environment, like DB_HOST, stay top-level unless
+// written with dots, while INI section keys and dotted .properties keys nest
+// like JSON. A view returned by Sub returns the values below its key.
+func (c *Config) AllNested() map[string]interface{} {
+	if c.root != nil {
+		return c.root.nested(c.prefix)
+	}
+	return c.nested("")
+}
+
+// nested returns the values below the environment name prefix, never nil
+func (c *Config) nested(prefix string) map[string]interface{} {
+	if tree := c.state.Load().nestedMap(prefix); tree != nil {
+		return tree
+	}
+	return make(map[string]interface{})
+}
+
+// WriteJSON writes the loaded values as an indented JSON document with sorted keys
+func (c *Config) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(c.AllNested()); err != nil {
+		return fmt.Errorf("failed to write JSON: %w", err)
+	}
+	return nil
+}
+
+// WriteYAML writes the loaded values as a YAML document with sorted keys
+func (c *Config) WriteYAML(w io.Writer) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(yamlValue(c.AllNested())); err != nil {
+		return fmt.Errorf("failed to write YAML: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return fmt.Errorf("failed to write YAML: %w", err)
+	}
+	return nil
+}
+
+// yamlValue replaces json.Number values, which yaml.v3 would write as strings, with plain numbers
+func yamlValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		converted := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			converted[key] = yamlValue(item)
+		}
+		return converted
+	case []interface{}:
+		converted := make([]interface{}, len(v))
+		for i, item := range v {
+			converted[i] = yamlValue(item)
+		}
+		return converted
+	case json.Number:
+		// A number node keeps the value exactly as written
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!" + numberTag(v), Value: v.String()}
+	}
+	return value
+}
+
+// numberTag returns the YAML tag of a JSON number
+func numberTag(n json.Number) string {
+	if _, err := n.Int64(); err == nil {
+		return "int"
+	}
+	return "float"
+}
+
+// WriteEnv writes the loaded values as a .env file sorted by key
+// Values are written in the string form the getters read, arrays joined with
+// commas. Values that need it are double-quoted, so LoadDotenv or a .env
+// source reads back exactly the same values. A view returned by Sub writes
+// the keys below its key, named relative to it.
+func (c *Config) WriteEnv(w io.Writer) error {
+	root := c
+	if c.root != nil {
+		root = c.root
+	}
+	values := root.state.Load().values
+	var b strings.Builder
+	for _, key := range sortedKeys(values) {
+		name, ok := strings.CutPrefix(key, c.prefix)
+		if !ok || name == "" {
+			continue
+		}
+		b.WriteString(name)
+		b.WriteByte('=')
+		b.WriteString(quoteEnvValue(values[key]))
+		b.WriteByte('\n')
+	}
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("failed to write .env: %w", err)
+	}
+	return nil
+}
+
+// quoteEnvValue returns value as parseEnv reads it back, double-quoted if needed
+func quoteEnvValue(value string) string {
+	if !strings.ContainsAny(value, " \t\r\n\"'`#$\\") {
+		return value
+	}
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `\$`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+	return `"` + replacer.Replace(value) + `"`
+}
@@ -0,0 +1,127 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestAllNested(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	content := `{"app": {"name": "demo", "ports": [80, 443], "servers": [{"host": "a"}, {"host": "b"}]}, "debug": true}`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	config := NewWithOptions(path,
+		WithIsolated(),
+		WithSources(DefaultsSource(map[string]interface{}{"LOG_LEVEL": "info"})),
+	)
+
+	expected := map[string]interface{}{
+		"app": map[string]interface{}{
+			"name":  "demo",
+			"ports": []interface{}{json.Number("80"), json.Number("443")},
+			"servers": []interface{}{
+				map[string]interface{}{"host": "a"},
+				map[string]interface{}{"host": "b"},
+			},
+		},
+		"debug":     true,
+		"LOG_LEVEL": "info",
+	}
+	if nested := config.AllNested(); !reflect.DeepEqual(nested, expected) {
+		t.Errorf("Expected %#v, got %#v", expected, nested)
+	}
+	if nested := config.Sub("app").AllNested(); !reflect.DeepEqual(nested, expected["app"]) {
+		t.Errorf("Expected the app subtree, got %#v", nested)
+	}
+	if nested := config.Sub("missing").AllNested(); nested == nil || len(nested) != 0 {
+		t.Errorf("Expected an empty map, got %#v", nested)
+	}
+}
+
+func TestWriteJSONAndYAML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	content := `{"b": {"size": 1e3, "list": [{"x": 1}]}, "a": "<x>", "c": null}`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	config := NewWithOptions(path, WithIsolated())
+
+	var out bytes.Buffer
+	if err := config.WriteJSON(&out); err != nil {
+		t.Fatalf("Failed to write JSON: %v", err)
+	}
+	expectedJSON := `{
+  "a": "<x>",
+  "b": {
+    "list": [
+      {
+        "x": 1
+      }
+    ],
+    "size": 1e3
+  },
+  "c": null
+}
+`
+	if out.String() != expectedJSON {
+		t.Errorf("Unexpected JSON:\n%s", out.String())
+	}
+
+	out.Reset()
+	if err := config.WriteYAML(&out); err != nil {
+		t.Fatalf("Failed to write YAML: %v", err)
+	}
+	expectedYAML := `a: <x>
+b:
+  list:
+    - x: 1
+  size: 1e3
+c: null
+`
+	if out.String() != expectedYAML {
+		t.Errorf("Unexpected YAML:\n%s", out.String())
+	}
+}
+
+func TestWriteEnvRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "legacy.env")
+	content := "B=plain\nA=\"two words # not a comment\"\nC='cost $5'\nD=\"line1\\nline2\"\nE=\nDB_HOST=db.local\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	config := NewWithOptions(path, WithIsolated())
+
+	var out bytes.Buffer
+	if err := config.WriteEnv(&out); err != nil {
+		t.Fatalf("Failed to write .env: %v", err)
+	}
+	expected := "A=\"two words # not a comment\"\nB=plain\nC=\"cost \\$5\"\nD=\"line1\\nline2\"\nDB_HOST=db.local\nE=\n"
+	if out.String() != expected {
+		t.Errorf("Unexpected .env output:\n%s", out.String())
+	}
+
+	written := filepath.Join(dir, "written.env")
+	if err := os.WriteFile(written, out.Bytes(), 0644); err != nil {
+		t.Fatalf("Failed to write .env file: %v", err)
+	}
+	reread := NewWithOptions(written, WithIsolated())
+	for _, key := range []string{"A", "B", "C", "D", "E", "DB_HOST"} {
+		if got, want := reread.Str(key), config.Str(key); got != want {
+			t.Errorf("Expected %s=%q after the round trip, got %q", key, want, got)
+		}
+	}
+
+	out.Reset()
+	if err := config.Sub("db").WriteEnv(&out); err != nil {
+		t.Fatalf("Failed to write .env: %v", err)
+	}
+	if out.String() != "HOST=db.local\n" {
+		t.Errorf("Expected only the view's keys, got %q", out.String())
+	}
+}